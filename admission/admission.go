@@ -0,0 +1,113 @@
+// Package admission reproduces Janus admission rules locally so that a config can
+// be evaluated against historical jobs before it is activated.
+package admission
+
+import (
+	"math"
+	"sort"
+
+	"janus-backend-api/models"
+)
+
+// Rejection reasons
+const (
+	ReasonMinPriority      = "below_min_priority"
+	ReasonTenantConcurrent = "tenant_concurrency_limit"
+	ReasonDependencyLimit  = "dependency_limit"
+)
+
+// Job is the part of a job that admission looks at
+type Job struct {
+	TenantID     string
+	Priority     int
+	Dependencies map[string]int
+}
+
+// JobFromPayload reads tenant, priority and dependencies from a stored job payload.
+// Missing or malformed values are treated as absent.
+func JobFromPayload(payload map[string]interface{}) Job {
+	job := Job{Dependencies: map[string]int{}}
+	if tenant, ok := payload["tenant_id"].(string); ok {
+		job.TenantID = tenant
+	}
+	if priority, ok := payload["priority"].(float64); ok {
+		job.Priority = int(math.Trunc(priority))
+	}
+	if deps, ok := payload["dependencies"].(map[string]interface{}); ok {
+		for name, v := range deps {
+			if n, ok := v.(float64); ok {
+				job.Dependencies[name] = int(math.Trunc(n))
+			}
+		}
+	}
+	return job
+}
+
+// Decision is the outcome of admitting one job
+type Decision struct {
+	Accepted bool
+	Reason   string
+	// Dependency names the exhausted dependency for ReasonDependencyLimit
+	Dependency string
+}
+
+// Admitter applies a config to a group of jobs that run at the same time.
+// Accepted jobs consume tenant and dependency capacity until Reset.
+type Admitter struct {
+	spec        models.JobConfigSpec
+	tenantSpecs map[string]models.JobConfigSpec
+	tenants     map[string]int
+	deps        map[string]int
+}
+
+// NewAdmitter creates an Admitter for spec
+func NewAdmitter(spec models.JobConfigSpec) *Admitter {
+	a := &Admitter{spec: spec, tenantSpecs: map[string]models.JobConfigSpec{}}
+	a.Reset()
+	return a
+}
+
+// SetTenantSpec applies spec instead of the base spec to the tenant's jobs
+func (a *Admitter) SetTenantSpec(tenantID string, spec models.JobConfigSpec) {
+	a.tenantSpecs[tenantID] = spec
+}
+
+// Reset releases all capacity, starting a new concurrent group
+func (a *Admitter) Reset() {
+	a.tenants = map[string]int{}
+	a.deps = map[string]int{}
+}
+
+// Admit decides whether job would be accepted. Rules are checked in the order
+// priority, tenant concurrency, dependency limits (by dependency name).
+func (a *Admitter) Admit(job Job) Decision {
+	spec, ok := a.tenantSpecs[job.TenantID]
+	if !ok {
+		spec = a.spec
+	}
+
+	if spec.MinPriority != nil && job.Priority < *spec.MinPriority {
+		return Decision{Reason: ReasonMinPriority}
+	}
+	if spec.MaxConcurrentPerTenant != nil && a.tenants[job.TenantID] >= *spec.MaxConcurrentPerTenant {
+		return Decision{Reason: ReasonTenantConcurrent}
+	}
+
+	names := make([]string, 0, len(job.Dependencies))
+	for name := range job.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		limit, ok := spec.DependencyLimits[name]
+		if ok && a.deps[name]+job.Dependencies[name] > limit {
+			return Decision{Reason: ReasonDependencyLimit, Dependency: name}
+		}
+	}
+
+	a.tenants[job.TenantID]++
+	for name, n := range job.Dependencies {
+		a.deps[name] += n
+	}
+	return Decision{Accepted: true}
+}
@@ -1,5 +1,13 @@
 package config
 
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // AppConfig holds application configuration
 type AppConfig struct {
 	ServerPort         string
@@ -9,17 +17,87 @@ type AppConfig struct {
 	GoogleClientID     string
 	GoogleClientSecret string
 	GoogleRedirectURL  string
+
+	// Largest accepted submission body, in bytes
+	MaxSubmitBodyBytes int64
+
+	// Shadow traffic mirroring to a candidate Janus deployment
+	ShadowJanusURL   string
+	ShadowSampleRate float64
+
+	// HMAC signing of requests proxied to Janus
+	JanusSigningKeyID  string
+	JanusSigningSecret string
+
+	// How often scheduled activations and windows are evaluated
+	ScheduleInterval time.Duration
+
+	// Delivery of active configs to Janus and drift checks
+	ConfigSyncInterval      time.Duration
+	ConfigReconcileInterval time.Duration
+
+	// How long a change request stays open before it expires
+	ChangeRequestTTL time.Duration
+
+	// How long deleted configs stay restorable before they are purged
+	ConfigTrashRetention time.Duration
+
+	// Payload paths that can be searched with GET /jobs?q=
+	JobQueryFields []string
+
+	// Where asynchronous exports are written and how long they are kept
+	ExportDir       string
+	ExportRetention time.Duration
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *AppConfig {
 	return &AppConfig{
-		ServerPort:         getEnv("SERVER_PORT", ""),
-		DatabaseURL:        getEnv("DATABASE_URL", ""),
-		JWTSecret:          getEnv("JWT_SECRET", ""),
-		JanusBaseURL:       getEnv("JANUS_BASE_URL", ""),
-		GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
-		GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
-		GoogleRedirectURL:  getEnv("GOOGLE_REDIRECT_URL", ""),
+		ServerPort:              getEnv("SERVER_PORT", ""),
+		DatabaseURL:             getEnv("DATABASE_URL", ""),
+		JWTSecret:               getEnv("JWT_SECRET", ""),
+		JanusBaseURL:            getEnv("JANUS_BASE_URL", ""),
+		GoogleClientID:          getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:      getEnv("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURL:       getEnv("GOOGLE_REDIRECT_URL", ""),
+		MaxSubmitBodyBytes:      getEnvInt64("MAX_SUBMIT_BODY_BYTES", 64<<20),
+		ShadowJanusURL:          getEnv("SHADOW_JANUS_URL", ""),
+		ShadowSampleRate:        getEnvFloat("SHADOW_SAMPLE_RATE", 0),
+		JanusSigningKeyID:       getEnv("JANUS_SIGNING_KEY_ID", "default"),
+		JanusSigningSecret:      getEnv("JANUS_SIGNING_SECRET", ""),
+		ScheduleInterval:        time.Duration(getEnvInt64("CONFIG_SCHEDULE_INTERVAL_SECONDS", 30)) * time.Second,
+		ConfigSyncInterval:      time.Duration(getEnvInt64("CONFIG_SYNC_INTERVAL_SECONDS", 15)) * time.Second,
+		ConfigReconcileInterval: time.Duration(getEnvInt64("CONFIG_RECONCILE_INTERVAL_SECONDS", 300)) * time.Second,
+		ChangeRequestTTL:        time.Duration(getEnvInt64("CHANGE_REQUEST_TTL_HOURS", 72)) * time.Hour,
+		ConfigTrashRetention:    time.Duration(getEnvInt64("CONFIG_TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		JobQueryFields:          getEnvList("JOB_QUERY_FIELDS", "tenant_id,priority,custom_key,customer_id"),
+		ExportDir:               getEnv("EXPORT_DIR", filepath.Join(os.TempDir(), "janus-exports")),
+		ExportRetention:         time.Duration(getEnvInt64("EXPORT_RETENTION_HOURS", 24)) * time.Hour,
+	}
+}
+
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(getEnv(key, ""), 10, 64)
+	if err != nil {
+		return defaultValue
 	}
+	return value
 }
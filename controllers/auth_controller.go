@@ -25,32 +25,32 @@ func NewAuthController() *AuthController {
 func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 	var req models.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	// Validate required fields
-	if req.Name == "" || req.Email == "" || req.Password == "" {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Name, email, and password are required"))
+	if details := requiredFields(map[string]string{"name": req.Name, "email": req.Email, "password": req.Password}); len(details) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Name, email, and password are required", details...)
 		return
 	}
 
 	if len(req.Password) < 6 {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Password must be at least 6 characters"))
+		respondError(w, r, models.ErrValidationFailed, "Password must be at least 6 characters", models.FieldError{Field: "password", Message: "must be at least 6 characters"})
 		return
 	}
 
 	// Check if email already exists
 	var existingUser models.User
 	if err := config.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-		respondJSON(w, http.StatusConflict, models.NewErrorResponse("Email already registered"))
+		respondError(w, r, models.ErrEmailTaken, "Email already registered")
 		return
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to process password"))
+		respondError(w, r, models.ErrInternal, "Failed to process password")
 		return
 	}
 
@@ -66,14 +66,14 @@ func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := config.DB.Create(&user).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to create user"))
+		respondError(w, r, models.ErrInternal, "Failed to create user")
 		return
 	}
 
 	// Generate JWT token
 	token, err := middleware.GenerateToken(user.UserID, req.Email)
 	if err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to generate token"))
+		respondError(w, r, models.ErrInternal, "Failed to generate token")
 		return
 	}
 
@@ -87,37 +87,37 @@ func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
 		return
 	}
 
-	if req.Email == "" || req.Password == "" {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Email and password are required"))
+	if details := requiredFields(map[string]string{"email": req.Email, "password": req.Password}); len(details) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Email and password are required", details...)
 		return
 	}
 
 	// Find user by email
 	var user models.User
 	if err := config.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("Invalid email or password"))
+		respondError(w, r, models.ErrInvalidCredentials, "Invalid email or password")
 		return
 	}
 
 	// Check password
 	if user.PasswordHash == nil {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("This account uses Google login"))
+		respondError(w, r, models.ErrPasswordLoginBlocked, "This account uses Google login")
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(req.Password)); err != nil {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("Invalid email or password"))
+		respondError(w, r, models.ErrInvalidCredentials, "Invalid email or password")
 		return
 	}
 
 	// Generate JWT token
 	token, err := middleware.GenerateToken(user.UserID, req.Email)
 	if err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to generate token"))
+		respondError(w, r, models.ErrInternal, "Failed to generate token")
 		return
 	}
 
@@ -131,13 +131,13 @@ func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 func (c *AuthController) Profile(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	var user models.User
 	if err := config.DB.Where("user_id = ?", userID).First(&user).Error; err != nil {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("User not found"))
+		respondError(w, r, models.ErrUserNotFound, "User not found")
 		return
 	}
 
@@ -147,11 +147,11 @@ func (c *AuthController) Profile(w http.ResponseWriter, r *http.Request) {
 // GoogleAuth handles GET /auth/google (placeholder)
 func (c *AuthController) GoogleAuth(w http.ResponseWriter, r *http.Request) {
 	// TODO: Implement Google OAuth redirect
-	respondJSON(w, http.StatusNotImplemented, models.NewErrorResponse("Google OAuth not configured. Please set GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET"))
+	respondError(w, r, models.ErrNotImplemented, "Google OAuth not configured. Please set GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET")
 }
 
 // GoogleCallback handles GET /auth/google/callback (placeholder)
 func (c *AuthController) GoogleCallback(w http.ResponseWriter, r *http.Request) {
 	// TODO: Implement Google OAuth callback
-	respondJSON(w, http.StatusNotImplemented, models.NewErrorResponse("Google OAuth not configured"))
+	respondError(w, r, models.ErrNotImplemented, "Google OAuth not configured")
 }
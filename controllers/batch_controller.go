@@ -9,6 +9,8 @@ import (
 	"janus-backend-api/models"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // BatchController handles batch viewing endpoints
@@ -23,7 +25,12 @@ func NewBatchController() *BatchController {
 func (c *BatchController) List(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	if wantsCursor(r.URL.Query()) {
+		c.listByCursor(w, r, userID)
 		return
 	}
 
@@ -49,7 +56,7 @@ func (c *BatchController) List(w http.ResponseWriter, r *http.Request) {
 		Offset(offset).
 		Limit(perPage).
 		Find(&batches).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch batches"))
+		respondError(w, r, models.ErrInternal, "Failed to fetch batches")
 		return
 	}
 
@@ -66,7 +73,7 @@ func (c *BatchController) List(w http.ResponseWriter, r *http.Request) {
 func (c *BatchController) Get(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -74,7 +81,7 @@ func (c *BatchController) Get(w http.ResponseWriter, r *http.Request) {
 
 	var batch models.Batch
 	if err := config.DB.Where("batch_id = ? AND user_id = ?", batchID, userID).First(&batch).Error; err != nil {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Batch not found"))
+		respondError(w, r, models.ErrBatchNotFound, "Batch not found")
 		return
 	}
 
@@ -85,7 +92,7 @@ func (c *BatchController) Get(w http.ResponseWriter, r *http.Request) {
 func (c *BatchController) GetJobs(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -94,7 +101,12 @@ func (c *BatchController) GetJobs(w http.ResponseWriter, r *http.Request) {
 	// Verify batch belongs to user
 	var batch models.Batch
 	if err := config.DB.Where("batch_id = ? AND user_id = ?", batchID, userID).First(&batch).Error; err != nil {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Batch not found"))
+		respondError(w, r, models.ErrBatchNotFound, "Batch not found")
+		return
+	}
+
+	if wantsCursor(r.URL.Query()) {
+		listJobsByCursor(w, r, config.DB.Model(&models.Job{}).Where("batch_id = ?", batchID), 50)
 		return
 	}
 
@@ -120,7 +132,7 @@ func (c *BatchController) GetJobs(w http.ResponseWriter, r *http.Request) {
 		Offset(offset).
 		Limit(perPage).
 		Find(&jobs).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch jobs"))
+		respondError(w, r, models.ErrInternal, "Failed to fetch jobs")
 		return
 	}
 
@@ -132,3 +144,37 @@ func (c *BatchController) GetJobs(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(responses, page, perPage, total))
 }
+
+// listByCursor responds with one cursor page of the user's batches
+func (c *BatchController) listByCursor(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	p, fieldErr := parseCursorPage(r.URL.Query(), 20, "batch_id")
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+
+	query := config.DB.Model(&models.Batch{}).Where("user_id = ?", userID)
+	total, estimated := p.countRows(query.Session(&gorm.Session{}), &[]models.Batch{})
+
+	var batches []models.Batch
+	if err := p.apply(query).Find(&batches).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch batches")
+		return
+	}
+	batches, next, prev := finishCursorPage(p, batches, batchCursor)
+
+	responses := make([]models.BatchResponse, len(batches))
+	for i, batch := range batches {
+		responses[i] = batch.ToResponse()
+	}
+
+	respondJSON(w, http.StatusOK, models.CursorResponse{
+		Success:         true,
+		Data:            responses,
+		PerPage:         p.limit,
+		NextCursor:      next,
+		PrevCursor:      prev,
+		TotalItems:      total,
+		TotalIsEstimate: estimated,
+	})
+}
@@ -0,0 +1,817 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxRequiredApprovals = 10
+	maxChangeRequestTTL  = 30 * 24 * time.Hour
+)
+
+// errChangeConflict means the config changed after the change request was proposed
+var errChangeConflict = errors.New("config changed since the change request was proposed")
+
+// ChangeRequestController handles approval policies, approvers and change requests
+type ChangeRequestController struct {
+	defaultTTL time.Duration
+}
+
+// NewChangeRequestController creates a new ChangeRequestController
+func NewChangeRequestController(defaultTTL time.Duration) *ChangeRequestController {
+	if defaultTTL <= 0 {
+		defaultTTL = 72 * time.Hour
+	}
+	return &ChangeRequestController{defaultTTL: defaultTTL}
+}
+
+// SetPolicy handles PUT /configs/{id}/approval-policy - require approvals for changes (owner only)
+func (c *ChangeRequestController) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var req models.ApprovalPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if fieldErr := normalizePolicy(&req); fieldErr != nil {
+		respondError(w, r, models.ErrValidationFailed, "Invalid approval policy", *fieldErr)
+		return
+	}
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	// Tightening takes effect at once; relaxing a gated config needs approval itself
+	if cfg.RequiresApproval && (!req.RequiresApproval || req.RequiredApprovals < cfg.RequiredApprovals) {
+		tx.Rollback()
+		respondError(w, r, models.ErrApprovalRequired, "Relaxing the approval policy requires approval; open a change request of kind policy at /configs/"+cfg.ConfigID.String()+"/change-requests")
+		return
+	}
+	if fieldErr := checkApproverCount(tx, cfg.ConfigID, &req, 0); fieldErr != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrValidationFailed, "Not enough approvers", *fieldErr)
+		return
+	}
+	cfg.RequiresApproval = req.RequiresApproval
+	cfg.RequiredApprovals = req.RequiredApprovals
+	if err := tx.Save(cfg).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to update approval policy")
+		return
+	}
+	tx.Commit()
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Approval policy updated", cfg.ToResponse()))
+}
+
+// Approvers handles GET /configs/{id}/approvers - list a config's approvers
+func (c *ChangeRequestController) Approvers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	if _, err := findMemberConfig(config.DB, userID, configID); err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	var approvers []models.ConfigApprover
+	if err := config.DB.Where("config_id = ?", configID).Order("created_at").Find(&approvers).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch approvers")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Approvers retrieved", approvers))
+}
+
+// AddApprover handles POST /configs/{id}/approvers - add an approver by email (owner only)
+func (c *ChangeRequestController) AddApprover(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var req models.AddApproverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if req.Email == "" {
+		respondError(w, r, models.ErrValidationFailed, "Email is required", models.FieldError{Field: "email", Message: "is required"})
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if cfg.RequiresApproval {
+		respondError(w, r, models.ErrApprovalRequired, "Adding an approver to this config requires approval; open a change request of kind add_approver at /configs/"+cfg.ConfigID.String()+"/change-requests")
+		return
+	}
+
+	var user models.User
+	if err := config.DB.Where("LOWER(email) = LOWER(?)", strings.TrimSpace(req.Email)).First(&user).Error; err != nil {
+		respondError(w, r, models.ErrUserNotFound, "No user with this email")
+		return
+	}
+	if user.UserID == userID {
+		respondError(w, r, models.ErrValidationFailed, "The owner cannot be an approver", models.FieldError{Field: "email", Message: "must belong to another user"})
+		return
+	}
+
+	approver := models.ConfigApprover{
+		ConfigID:  configID,
+		UserID:    user.UserID,
+		AddedBy:   userID,
+		CreatedAt: time.Now(),
+	}
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&approver).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to add approver")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Approver added", approver))
+}
+
+// RemoveApprover handles DELETE /configs/{id}/approvers/{userId} - remove an approver (owner only)
+func (c *ChangeRequestController) RemoveApprover(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	approverID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid user ID")
+		return
+	}
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if cfg.RequiresApproval {
+		tx.Rollback()
+		respondError(w, r, models.ErrApprovalRequired, "Removing an approver from this config requires approval; open a change request of kind remove_approver at /configs/"+cfg.ConfigID.String()+"/change-requests")
+		return
+	}
+
+	removed, err := removeApprover(tx, configID, approverID, uuid.Nil)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to remove approver")
+		return
+	}
+	if !removed {
+		tx.Rollback()
+		respondError(w, r, models.ErrUserNotFound, "User is not an approver of this config")
+		return
+	}
+	tx.Commit()
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Approver removed", nil))
+}
+
+// Create handles POST /configs/{id}/change-requests - propose an update, activation, deactivation or policy change
+func (c *ChangeRequestController) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var req models.CreateChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	var details []models.FieldError
+	switch req.Kind {
+	case models.ChangeKindUpdate:
+		if req.ConfigName == "" && req.Config == nil {
+			details = append(details, models.FieldError{Field: "config", Message: "config or config_name is required for update"})
+		}
+		if req.Config != nil {
+			details = append(details, models.ValidateConfig(req.Config, req.AllowUnknownKeys)...)
+		}
+	case models.ChangeKindActivate, models.ChangeKindDeactivate:
+		if req.ConfigName != "" || req.Config != nil {
+			details = append(details, models.FieldError{Field: "config", Message: "must be empty for " + req.Kind})
+		}
+	case models.ChangeKindPolicy:
+		if req.ConfigName != "" || req.Config != nil {
+			details = append(details, models.FieldError{Field: "config", Message: "must be empty for policy"})
+		}
+		if req.Policy == nil {
+			details = append(details, models.FieldError{Field: "policy", Message: "is required for policy"})
+		} else if fieldErr := normalizePolicy(req.Policy); fieldErr != nil {
+			fieldErr.Field = "policy." + fieldErr.Field
+			details = append(details, *fieldErr)
+		}
+	case models.ChangeKindAddApprover, models.ChangeKindRemoveApprover:
+		if req.ConfigName != "" || req.Config != nil {
+			details = append(details, models.FieldError{Field: "config", Message: "must be empty for " + req.Kind})
+		}
+		req.ApproverEmail = strings.TrimSpace(req.ApproverEmail)
+		if req.ApproverEmail == "" {
+			details = append(details, models.FieldError{Field: "approver_email", Message: "is required for " + req.Kind})
+		}
+	default:
+		details = append(details, models.FieldError{Field: "kind", Message: "must be update, activate, deactivate, policy, add_approver or remove_approver"})
+	}
+	if req.Policy != nil && req.Kind != models.ChangeKindPolicy {
+		details = append(details, models.FieldError{Field: "policy", Message: "is only allowed for policy"})
+	}
+	if req.ApproverEmail != "" && req.Kind != models.ChangeKindAddApprover && req.Kind != models.ChangeKindRemoveApprover {
+		details = append(details, models.FieldError{Field: "approver_email", Message: "is only allowed for add_approver and remove_approver"})
+	}
+	ttl := c.defaultTTL
+	if req.ExpiresInHours != 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+		if ttl <= 0 || ttl > maxChangeRequestTTL {
+			details = append(details, models.FieldError{Field: "expires_in_hours", Message: "must be between 1 and 720"})
+		}
+	}
+	if len(details) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Invalid change request", details...)
+		return
+	}
+
+	cfg, err := findMemberConfig(config.DB, userID, configID)
+	if err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if !cfg.RequiresApproval {
+		respondError(w, r, models.ErrValidationFailed, "Config does not require approval; change it directly", models.FieldError{Field: "config_id", Message: "does not require approval"})
+		return
+	}
+
+	now := time.Now()
+	cr := models.ChangeRequest{
+		ChangeRequestID:   uuid.New(),
+		ConfigID:          cfg.ConfigID,
+		OwnerID:           cfg.UserID,
+		AuthorID:          userID,
+		Kind:              req.Kind,
+		ProposedConfig:    req.Config,
+		BaseVersion:       cfg.CurrentVersion,
+		Status:            models.ChangeOpen,
+		RequiredApprovals: cfg.RequiredApprovals,
+		ExpiresAt:         now.Add(ttl),
+		CreatedAt:         now,
+	}
+	if req.ConfigName != "" {
+		cr.ProposedName = &req.ConfigName
+	}
+	if req.Policy != nil {
+		if fieldErr := checkApproverCount(config.DB, cfg.ConfigID, req.Policy, 0); fieldErr != nil {
+			fieldErr.Field = "policy." + fieldErr.Field
+			respondError(w, r, models.ErrValidationFailed, "Not enough approvers", *fieldErr)
+			return
+		}
+		cr.ProposedRequiresApproval = &req.Policy.RequiresApproval
+		cr.ProposedRequiredApprovals = &req.Policy.RequiredApprovals
+	}
+	if req.ApproverEmail != "" {
+		var user models.User
+		if err := config.DB.Where("LOWER(email) = LOWER(?)", req.ApproverEmail).First(&user).Error; err != nil {
+			respondError(w, r, models.ErrUserNotFound, "No user with this email")
+			return
+		}
+		isApprover := isConfigApprover(config.DB, cfg.ConfigID, user.UserID)
+		switch {
+		case req.Kind == models.ChangeKindAddApprover && user.UserID == cfg.UserID:
+			respondError(w, r, models.ErrValidationFailed, "The owner cannot be an approver", models.FieldError{Field: "approver_email", Message: "must belong to another user"})
+			return
+		case req.Kind == models.ChangeKindAddApprover && isApprover:
+			respondError(w, r, models.ErrValidationFailed, "User is already an approver", models.FieldError{Field: "approver_email", Message: "is already an approver"})
+			return
+		case req.Kind == models.ChangeKindRemoveApprover && !isApprover:
+			respondError(w, r, models.ErrUserNotFound, "User is not an approver of this config")
+			return
+		case req.Kind == models.ChangeKindRemoveApprover:
+			policy := models.ApprovalPolicyRequest{RequiresApproval: true, RequiredApprovals: cfg.RequiredApprovals}
+			if fieldErr := checkApproverCount(config.DB, cfg.ConfigID, &policy, 1); fieldErr != nil {
+				respondError(w, r, models.ErrValidationFailed, "Not enough approvers", models.FieldError{Field: "approver_email", Message: "removing this approver would leave fewer approvers than required_approvals"})
+				return
+			}
+		}
+		cr.ProposedApproverID = &user.UserID
+	}
+
+	tx := config.DB.Begin()
+	if err := tx.Create(&cr).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create change request")
+		return
+	}
+	if err := addChangeEvent(tx, &cr, userID, models.ChangeEventCreated, req.Comment); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create change request")
+		return
+	}
+	tx.Commit()
+
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Change request created", cr))
+}
+
+// List handles GET /change-requests - change requests on configs the user owns or approves
+func (c *ChangeRequestController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := config.DB.Model(&models.ChangeRequest{}).
+		Where("owner_id = ? OR author_id = ? OR config_id IN (?)", userID, userID,
+			config.DB.Model(&models.ConfigApprover{}).Select("config_id").Where("user_id = ?", userID))
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if configID := r.URL.Query().Get("config_id"); configID != "" {
+		id, err := uuid.Parse(configID)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid config_id", models.FieldError{Field: "config_id", Message: "must be a UUID"})
+			return
+		}
+		query = query.Where("config_id = ?", id)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var requests []models.ChangeRequest
+	offset := (page - 1) * perPage
+	if err := query.Order("created_at DESC").Offset(offset).Limit(perPage).Find(&requests).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch change requests")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(requests, page, perPage, total))
+}
+
+// Get handles GET /change-requests/{id} - a change request with its audit trail
+func (c *ChangeRequestController) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	crID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid change request ID")
+		return
+	}
+
+	var cr models.ChangeRequest
+	if err := config.DB.Where("change_request_id = ?", crID).First(&cr).Error; err != nil {
+		respondError(w, r, models.ErrChangeNotFound, "Change request not found")
+		return
+	}
+	cfg, err := findMemberConfig(config.DB, userID, cr.ConfigID)
+	if err != nil && cr.AuthorID != userID {
+		respondError(w, r, models.ErrChangeNotFound, "Change request not found")
+		return
+	}
+
+	var events []models.ChangeRequestEvent
+	if err := config.DB.Where("change_request_id = ?", crID).Order("created_at").Find(&events).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch change request events")
+		return
+	}
+
+	resp := models.ChangeRequestResponse{ChangeRequest: cr, Events: events}
+	if cfg != nil && cr.Status == models.ChangeOpen && cr.ProposedConfig != nil {
+		resp.Changes = models.DiffConfigs(cfg.Config, cr.ProposedConfig)
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Change request retrieved", resp))
+}
+
+// Approve handles POST /change-requests/{id}/approve - approve; the change is applied
+// as soon as it has enough approvals
+func (c *ChangeRequestController) Approve(w http.ResponseWriter, r *http.Request) {
+	c.act(w, r, models.ChangeEventApproved)
+}
+
+// Reject handles POST /change-requests/{id}/reject - reject and close the change request
+func (c *ChangeRequestController) Reject(w http.ResponseWriter, r *http.Request) {
+	c.act(w, r, models.ChangeEventRejected)
+}
+
+// Comment handles POST /change-requests/{id}/comment - add a comment to the audit trail
+func (c *ChangeRequestController) Comment(w http.ResponseWriter, r *http.Request) {
+	c.act(w, r, models.ChangeEventCommented)
+}
+
+// Cancel handles POST /change-requests/{id}/cancel - withdraw a change request (author or owner)
+func (c *ChangeRequestController) Cancel(w http.ResponseWriter, r *http.Request) {
+	c.act(w, r, models.ChangeEventCancelled)
+}
+
+// act performs an action on an open change request under a row lock
+func (c *ChangeRequestController) act(w http.ResponseWriter, r *http.Request, action string) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	crID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid change request ID")
+		return
+	}
+
+	var req models.ChangeRequestAction
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if action == models.ChangeEventCommented && strings.TrimSpace(req.Comment) == "" {
+		respondError(w, r, models.ErrValidationFailed, "Comment is required", models.FieldError{Field: "comment", Message: "is required"})
+		return
+	}
+
+	tx := config.DB.Begin()
+	var cr models.ChangeRequest
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("change_request_id = ?", crID).First(&cr).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrChangeNotFound, "Change request not found")
+		return
+	}
+	if _, err := findMemberConfig(tx, userID, cr.ConfigID); err != nil && cr.AuthorID != userID {
+		tx.Rollback()
+		respondError(w, r, models.ErrChangeNotFound, "Change request not found")
+		return
+	}
+
+	// Expire lazily as well, so nothing is approved after its deadline
+	now := time.Now()
+	if cr.Status == models.ChangeOpen && !now.Before(cr.ExpiresAt) {
+		if err := closeChangeRequest(tx, &cr, uuid.Nil, models.ChangeExpired, models.ChangeEventExpired, ""); err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to update change request")
+			return
+		}
+		tx.Commit()
+		respondError(w, r, models.ErrChangeClosed, "Change request has expired")
+		return
+	}
+	if cr.Status != models.ChangeOpen && action != models.ChangeEventCommented {
+		tx.Rollback()
+		respondError(w, r, models.ErrChangeClosed, "Change request is "+cr.Status)
+		return
+	}
+
+	message := "Comment added"
+	applied := false
+	switch action {
+	case models.ChangeEventCommented:
+		err = addChangeEvent(tx, &cr, userID, action, req.Comment)
+	case models.ChangeEventCancelled:
+		if userID != cr.AuthorID && userID != cr.OwnerID {
+			tx.Rollback()
+			respondError(w, r, models.ErrForbidden, "Only the author or the config owner can cancel a change request")
+			return
+		}
+		message = "Change request cancelled"
+		err = closeChangeRequest(tx, &cr, userID, models.ChangeCancelled, action, req.Comment)
+	case models.ChangeEventRejected:
+		if userID == cr.AuthorID {
+			tx.Rollback()
+			respondError(w, r, models.ErrSelfApproval, "Authors cannot reject their own change; cancel it instead")
+			return
+		}
+		message = "Change request rejected"
+		err = closeChangeRequest(tx, &cr, userID, models.ChangeRejected, action, req.Comment)
+	case models.ChangeEventApproved:
+		if userID == cr.AuthorID {
+			tx.Rollback()
+			respondError(w, r, models.ErrSelfApproval, "Authors cannot approve their own change")
+			return
+		}
+		if hasApproved(tx, cr.ChangeRequestID, userID) {
+			tx.Rollback()
+			respondError(w, r, models.ErrAlreadyApproved, "You have already approved this change")
+			return
+		}
+
+		message = "Change request approved"
+		cr.Approvals++
+		if err = addChangeEvent(tx, &cr, userID, action, req.Comment); err == nil {
+			err = tx.Save(&cr).Error
+		}
+		if err == nil && cr.Approvals >= cr.RequiredApprovals {
+			err = applyChangeRequest(tx, &cr, userID)
+			if errors.Is(err, errChangeConflict) {
+				err = closeChangeRequest(tx, &cr, userID, models.ChangeConflicted, models.ChangeEventConflict, errChangeConflict.Error())
+				message = "Change request approved but could not be applied: " + errChangeConflict.Error()
+			} else if err == nil {
+				applied = true
+				message = "Change request approved and applied"
+			}
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to update change request")
+		return
+	}
+	tx.Commit()
+	if applied {
+		requestConfigSync()
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse(message, cr))
+}
+
+// applyChangeRequest applies an approved change to its config
+func applyChangeRequest(tx *gorm.DB, cr *models.ChangeRequest, approverID uuid.UUID) error {
+	cfg, err := lockUserConfig(tx, cr.OwnerID, cr.ConfigID)
+	if err != nil {
+		return err
+	}
+
+	switch cr.Kind {
+	case models.ChangeKindUpdate:
+		if cfg.CurrentVersion != cr.BaseVersion {
+			return errChangeConflict
+		}
+		if cr.ProposedName != nil {
+			cfg.ConfigName = cr.ProposedName
+		}
+		if cr.ProposedConfig != nil {
+			cfg.Config = cr.ProposedConfig
+		}
+		if err := appendRevision(tx, cfg, cr.AuthorID, models.RevisionUpdated, nil); err != nil {
+			return err
+		}
+	case models.ChangeKindActivate:
+		if err := activateConfig(tx, cfg, activation{Trigger: models.TriggerChangeRequest, ActorID: &approverID}); err != nil {
+			return err
+		}
+	case models.ChangeKindDeactivate:
+		if err := deactivateConfig(tx, cfg, activation{Trigger: models.TriggerChangeRequest, ActorID: &approverID}); err != nil {
+			return err
+		}
+	case models.ChangeKindAddApprover:
+		if cr.ProposedApproverID == nil {
+			return errors.New("approver change request has no proposed approver")
+		}
+		approver := models.ConfigApprover{
+			ConfigID:  cfg.ConfigID,
+			UserID:    *cr.ProposedApproverID,
+			AddedBy:   cr.AuthorID,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&approver).Error; err != nil {
+			return err
+		}
+	case models.ChangeKindRemoveApprover:
+		if cr.ProposedApproverID == nil {
+			return errors.New("approver change request has no proposed approver")
+		}
+		// Approvers may have changed since; never leave too few to approve anything
+		policy := models.ApprovalPolicyRequest{RequiresApproval: cfg.RequiresApproval, RequiredApprovals: cfg.RequiredApprovals}
+		if checkApproverCount(tx, cfg.ConfigID, &policy, 1) != nil {
+			return errChangeConflict
+		}
+		if _, err := removeApprover(tx, cfg.ConfigID, *cr.ProposedApproverID, cr.ChangeRequestID); err != nil {
+			return err
+		}
+	case models.ChangeKindPolicy:
+		if cr.ProposedRequiresApproval == nil || cr.ProposedRequiredApprovals == nil {
+			return errors.New("policy change request has no proposed policy")
+		}
+		cfg.RequiresApproval = *cr.ProposedRequiresApproval
+		cfg.RequiredApprovals = *cr.ProposedRequiredApprovals
+		if err := tx.Model(cfg).Updates(map[string]interface{}{
+			"requires_approval":  cfg.RequiresApproval,
+			"required_approvals": cfg.RequiredApprovals,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	version := cfg.CurrentVersion
+	cr.AppliedVersion = &version
+	return closeChangeRequest(tx, cr, approverID, models.ChangeApplied, models.ChangeEventApplied, "")
+}
+
+// closeChangeRequest resolves a change request and records why
+func closeChangeRequest(tx *gorm.DB, cr *models.ChangeRequest, actorID uuid.UUID, status, action, comment string) error {
+	now := time.Now()
+	cr.Status = status
+	cr.ResolvedAt = &now
+	if err := tx.Save(cr).Error; err != nil {
+		return err
+	}
+	return addChangeEvent(tx, cr, actorID, action, comment)
+}
+
+func addChangeEvent(tx *gorm.DB, cr *models.ChangeRequest, actorID uuid.UUID, action, comment string) error {
+	event := models.ChangeRequestEvent{
+		EventID:         uuid.New(),
+		ChangeRequestID: cr.ChangeRequestID,
+		ActorID:         actorID,
+		Action:          action,
+		CreatedAt:       time.Now(),
+	}
+	if comment != "" {
+		event.Comment = &comment
+	}
+	return tx.Create(&event).Error
+}
+
+// findMemberConfig loads a config owned by userID or on which userID is an approver
+func findMemberConfig(db *gorm.DB, userID, configID uuid.UUID) (*models.GlobalJobConfig, error) {
+	var cfg models.GlobalJobConfig
+	err := db.Where("config_id = ? AND (user_id = ? OR config_id IN (?))", configID, userID,
+		db.Session(&gorm.Session{NewDB: true}).Model(&models.ConfigApprover{}).Select("config_id").Where("user_id = ?", userID)).
+		First(&cfg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// expireChangeRequests closes open change requests past their deadline
+func expireChangeRequests(now time.Time) {
+	var ids []uuid.UUID
+	config.DB.Model(&models.ChangeRequest{}).Where("status = ? AND expires_at <= ?", models.ChangeOpen, now).Pluck("change_request_id", &ids)
+
+	for _, id := range ids {
+		tx := config.DB.Begin()
+		var cr models.ChangeRequest
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("change_request_id = ? AND status = ?", id, models.ChangeOpen).
+			First(&cr).Error
+		if err == nil {
+			err = closeChangeRequest(tx, &cr, uuid.Nil, models.ChangeExpired, models.ChangeEventExpired, "")
+		}
+		if err != nil {
+			tx.Rollback()
+			continue
+		}
+		tx.Commit()
+	}
+}
+
+// normalizePolicy defaults required_approvals to 1 and checks its range
+func normalizePolicy(req *models.ApprovalPolicyRequest) *models.FieldError {
+	if req.RequiredApprovals == 0 {
+		req.RequiredApprovals = 1
+	}
+	if req.RequiredApprovals < 1 || req.RequiredApprovals > maxRequiredApprovals {
+		return &models.FieldError{Field: "required_approvals", Message: "must be between 1 and " + strconv.Itoa(maxRequiredApprovals)}
+	}
+	return nil
+}
+
+// requireNoApproval reports APPROVAL_REQUIRED for configs that only change through change requests
+func requireNoApproval(w http.ResponseWriter, r *http.Request, cfg *models.GlobalJobConfig) bool {
+	if !cfg.RequiresApproval {
+		return true
+	}
+	respondError(w, r, models.ErrApprovalRequired, "This config requires approval; open a change request at /configs/"+cfg.ConfigID.String()+"/change-requests")
+	return false
+}
+
+// checkApproverCount makes sure a policy can be met: a change the owner proposes
+// needs required_approvals approvers besides the owner. removing is how many
+// approvers are about to be removed.
+func checkApproverCount(db *gorm.DB, configID uuid.UUID, policy *models.ApprovalPolicyRequest, removing int) *models.FieldError {
+	if !policy.RequiresApproval {
+		return nil
+	}
+	var approvers int64
+	db.Model(&models.ConfigApprover{}).Where("config_id = ?", configID).Count(&approvers)
+	if int(approvers)-removing < policy.RequiredApprovals {
+		return &models.FieldError{Field: "required_approvals", Message: "must not exceed the number of approvers (" + strconv.FormatInt(approvers-int64(removing), 10) + ")"}
+	}
+	return nil
+}
+
+// isConfigApprover reports whether userID is an approver of configID
+func isConfigApprover(db *gorm.DB, configID, userID uuid.UUID) bool {
+	var n int64
+	db.Model(&models.ConfigApprover{}).Where("config_id = ? AND user_id = ?", configID, userID).Count(&n)
+	return n > 0
+}
+
+// hasApproved reports whether userID's approval of a change request still counts
+func hasApproved(db *gorm.DB, crID, userID uuid.UUID) bool {
+	var approved, dropped int64
+	db.Model(&models.ChangeRequestEvent{}).
+		Where("change_request_id = ? AND actor_id = ? AND action = ?", crID, userID, models.ChangeEventApproved).
+		Count(&approved)
+	db.Model(&models.ChangeRequestEvent{}).
+		Where("change_request_id = ? AND actor_id = ? AND action = ?", crID, userID, models.ChangeEventApprovalDropped).
+		Count(&dropped)
+	return approved > dropped
+}
+
+// removeApprover removes userID from the approvers of configID and drops their
+// approvals from its open change requests other than except. It reports whether
+// userID was an approver.
+func removeApprover(tx *gorm.DB, configID, userID, except uuid.UUID) (bool, error) {
+	result := tx.Where("config_id = ? AND user_id = ?", configID, userID).Delete(&models.ConfigApprover{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	var open []models.ChangeRequest
+	err := tx.Where("config_id = ? AND status = ? AND change_request_id <> ?", configID, models.ChangeOpen, except).Find(&open).Error
+	if err != nil {
+		return false, err
+	}
+	for i := range open {
+		if !hasApproved(tx, open[i].ChangeRequestID, userID) {
+			continue
+		}
+		if err := tx.Model(&open[i]).Update("approvals", gorm.Expr("approvals - 1")).Error; err != nil {
+			return false, err
+		}
+		// The event is the approver's, so hasApproved stops counting their approval
+		if err := addChangeEvent(tx, &open[i], userID, models.ChangeEventApprovalDropped, "approver removed"); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"janus-backend-api/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activation describes who or what caused an activation change
+type activation struct {
+	Trigger    string
+	ScheduleID *uuid.UUID
+	ActorID    *uuid.UUID
+}
+
+// activateConfig makes cfg the user's only active config and records the switch.
+// cfg must already be locked in tx (see lockUserConfig).
+func activateConfig(tx *gorm.DB, cfg *models.GlobalJobConfig, by activation) error {
+	var previous models.GlobalJobConfig
+	var previousID *uuid.UUID
+	err := tx.Where("user_id = ? AND status = ?", cfg.UserID, models.ConfigStatusActive).First(&previous).Error
+	if err == nil {
+		previousID = &previous.ConfigID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// Deactivate all other configs for this user
+	if err := tx.Model(&models.GlobalJobConfig{}).
+		Where("user_id = ? AND status = ?", cfg.UserID, models.ConfigStatusActive).
+		Update("status", models.ConfigStatusInactive).Error; err != nil {
+		return err
+	}
+
+	// Activate the specified config and queue it for delivery to Janus; the push
+	// replaces any pending clear
+	if err := tx.Where("user_id = ?", cfg.UserID).Delete(&models.JanusConfigClear{}).Error; err != nil {
+		return err
+	}
+	result := tx.Model(&models.GlobalJobConfig{}).
+		Where("config_id = ? AND user_id = ?", cfg.ConfigID, cfg.UserID).
+		Updates(map[string]interface{}{
+			"status":        models.ConfigStatusActive,
+			"sync_status":   models.SyncPending,
+			"sync_error":    nil,
+			"sync_attempts": 0,
+			"next_sync_at":  nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	cfg.Status = models.ConfigStatusActive
+	markSyncPending(cfg)
+
+	return recordActivation(tx, cfg, models.ActionActivated, previousID, by)
+}
+
+// deactivateConfig marks cfg inactive and records the change. Deactivating the
+// active config leaves the user without one, which is queued for Janus.
+func deactivateConfig(tx *gorm.DB, cfg *models.GlobalJobConfig, by activation) error {
+	result := tx.Model(&models.GlobalJobConfig{}).
+		Where("config_id = ? AND user_id = ?", cfg.ConfigID, cfg.UserID).
+		Update("status", models.ConfigStatusInactive)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	wasActive := cfg.Status == models.ConfigStatusActive
+	cfg.Status = models.ConfigStatusInactive
+
+	if !wasActive {
+		return nil
+	}
+	if err := queueConfigClear(tx, cfg.UserID, nil); err != nil {
+		return err
+	}
+	return recordActivation(tx, cfg, models.ActionDeactivated, nil, by)
+}
+
+// queueConfigClear asks the sync worker to tell Janus that userID has no active
+// config, restarting any pending request
+func queueConfigClear(tx *gorm.DB, userID uuid.UUID, reason *string) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"queued_at", "attempts", "last_error", "next_sync_at"}),
+	}).Create(&models.JanusConfigClear{UserID: userID, QueuedAt: time.Now(), LastError: reason}).Error
+}
+
+func recordActivation(tx *gorm.DB, cfg *models.GlobalJobConfig, action string, previousID *uuid.UUID, by activation) error {
+	return tx.Create(&models.ConfigActivation{
+		ActivationID:     uuid.New(),
+		UserID:           cfg.UserID,
+		ConfigID:         cfg.ConfigID,
+		Action:           action,
+		PreviousConfigID: previousID,
+		Trigger:          by.Trigger,
+		ScheduleID:       by.ScheduleID,
+		ActorID:          by.ActorID,
+	}).Error
+}
+
+// manualActivation is the activation source for requests made by userID
+func manualActivation(userID uuid.UUID) activation {
+	return activation{Trigger: models.TriggerManual, ActorID: &userID}
+}
@@ -2,7 +2,12 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
 	"janus-backend-api/config"
 	"janus-backend-api/middleware"
@@ -13,69 +18,179 @@ import (
 )
 
 // ConfigController handles config management endpoints
-type ConfigController struct{}
+type ConfigController struct {
+	trashRetention time.Duration
+}
+
+// NewConfigController creates a new ConfigController. Deleted configs can be
+// restored for trashRetention before they are purged.
+func NewConfigController(trashRetention time.Duration) *ConfigController {
+	return &ConfigController{trashRetention: trashRetention}
+}
 
-// NewConfigController creates a new ConfigController
-func NewConfigController() *ConfigController {
-	return &ConfigController{}
+// configSortColumns maps the sort query param to columns
+var configSortColumns = map[string]string{
+	"name":       "config_name",
+	"status":     "status",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
 }
 
-// List handles GET /configs - list all user's configs
+// List handles GET /configs - list the user's configs with filtering, sorting and pagination
 func (c *ConfigController) List(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	// Build query
+	q := r.URL.Query()
+	query := config.DB.Model(&models.GlobalJobConfig{}).Where("user_id = ?", userID)
+	if name := q.Get("name"); name != "" {
+		query = query.Where("config_name ILIKE ?", "%"+escapeLike(name)+"%")
+	}
+	if labels := q["label"]; len(labels) > 0 {
+		encoded, _ := json.Marshal(labels)
+		query = query.Where("labels @> ?::jsonb", string(encoded))
+	}
+	switch status := q.Get("status"); status {
+	case "":
+	case string(models.ConfigStatusActive), string(models.ConfigStatusInactive):
+		query = query.Where("status = ?", status)
+	default:
+		respondError(w, r, models.ErrInvalidQueryParam, "status must be active or inactive", models.FieldError{Field: "status", Message: "must be active or inactive"})
 		return
 	}
+	for _, bound := range []struct{ param, condition string }{
+		{"created_from", "created_at >= ?"},
+		{"created_to", "created_at < ?"},
+		{"updated_from", "updated_at >= ?"},
+		{"updated_to", "updated_at < ?"},
+	} {
+		value := q.Get(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+bound.param+" time, use RFC 3339", models.FieldError{Field: bound.param, Message: "must be an RFC 3339 timestamp"})
+			return
+		}
+		query = query.Where(bound.condition, t)
+	}
+
+	sortColumn := "created_at"
+	if sortParam := q.Get("sort"); sortParam != "" {
+		column, ok := configSortColumns[sortParam]
+		if !ok {
+			respondError(w, r, models.ErrInvalidQueryParam, "sort must be name, status, created_at or updated_at", models.FieldError{Field: "sort", Message: "must be name, status, created_at or updated_at"})
+			return
+		}
+		sortColumn = column
+	}
+	direction := "DESC"
+	switch q.Get("order") {
+	case "", "desc":
+	case "asc":
+		direction = "ASC"
+	default:
+		respondError(w, r, models.ErrInvalidQueryParam, "order must be asc or desc", models.FieldError{Field: "order", Message: "must be asc or desc"})
+		return
+	}
+
+	// Count total
+	var total int64
+	query.Count(&total)
 
+	// Fetch configs; config_id keeps pages stable when sort values tie
 	var configs []models.GlobalJobConfig
-	if err := config.DB.Where("user_id = ?", userID).Find(&configs).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch configs"))
+	offset := (page - 1) * perPage
+	if err := query.Order(sortColumn + " " + direction).Order("config_id").Offset(offset).Limit(perPage).Find(&configs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch configs")
 		return
 	}
 
-	// Convert to response format
+	// Convert to response format, with a usage summary for each config
+	ids := make([]uuid.UUID, len(configs))
+	for i, cfg := range configs {
+		ids[i] = cfg.ConfigID
+	}
+	usage, err := configUsage(ids)
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch config usage")
+		return
+	}
 	responses := make([]models.ConfigResponse, len(configs))
 	for i, cfg := range configs {
 		responses[i] = cfg.ToResponse()
+		responses[i].Usage = &usage[cfg.ConfigID].ConfigUsage
 	}
 
-	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Configs retrieved", responses))
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(responses, page, perPage, total))
 }
 
 // GetActive handles GET /configs/active - get currently active config
 func (c *ConfigController) GetActive(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	var cfg models.GlobalJobConfig
 	if err := config.DB.Where("user_id = ? AND status = ?", userID, models.ConfigStatusActive).First(&cfg).Error; err != nil {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("No active config found"))
+		respondError(w, r, models.ErrNoActiveConfig, "No active config found")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Active config retrieved", cfg.ToResponse()))
 }
 
+// Schema handles GET /configs/schema - JSON Schema for config documents
+func (c *ConfigController) Schema(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, models.ConfigJSONSchema())
+}
+
 // Create handles POST /configs - create a new config
 func (c *ConfigController) Create(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	var req models.CreateConfigRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	if req.ConfigName == "" {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Config name is required"))
+		respondError(w, r, models.ErrValidationFailed, "Config name is required", models.FieldError{Field: "config_name", Message: "is required"})
+		return
+	}
+	if req.Config == nil {
+		respondError(w, r, models.ErrValidationFailed, "Config is required", models.FieldError{Field: "config", Message: "is required"})
+		return
+	}
+	if errs := models.ValidateConfig(req.Config, req.AllowUnknownKeys); len(errs) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Config failed validation", errs...)
+		return
+	}
+	labels, errs := models.NormalizeLabels("labels", req.Labels)
+	if len(errs) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Invalid labels", errs...)
 		return
 	}
 
@@ -85,13 +200,25 @@ func (c *ConfigController) Create(w http.ResponseWriter, r *http.Request) {
 		ConfigName: &req.ConfigName,
 		Config:     req.Config,
 		Status:     models.ConfigStatusInactive,
+		Labels:     labels,
+		CreatedBy:  &userID,
 	}
 
-	if err := config.DB.Create(&cfg).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to create config"))
+	// Create the config together with its first revision
+	tx := config.DB.Begin()
+	if err := tx.Create(&cfg).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create config")
 		return
 	}
+	if err := appendRevision(tx, &cfg, userID, models.RevisionCreated, nil); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create config")
+		return
+	}
+	tx.Commit()
 
+	setConfigETag(w, &cfg)
 	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Config created", cfg.ToResponse()))
 }
 
@@ -99,19 +226,26 @@ func (c *ConfigController) Create(w http.ResponseWriter, r *http.Request) {
 func (c *ConfigController) Get(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	configID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid config ID"))
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
 		return
 	}
 
 	var cfg models.GlobalJobConfig
 	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Config not found"))
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	etag := configETag(&cfg)
+	w.Header().Set("ETag", etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -122,25 +256,43 @@ func (c *ConfigController) Get(w http.ResponseWriter, r *http.Request) {
 func (c *ConfigController) Update(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	configID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid config ID"))
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
 		return
 	}
 
-	var cfg models.GlobalJobConfig
-	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Config not found"))
+	var req models.UpdateConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
 		return
 	}
 
-	var req models.UpdateConfigRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	if req.Config != nil {
+		if errs := models.ValidateConfig(req.Config, req.AllowUnknownKeys); len(errs) > 0 {
+			respondError(w, r, models.ErrValidationFailed, "Config failed validation", errs...)
+			return
+		}
+	}
+
+	// Lock the config so concurrent updates get consecutive versions
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if !checkIfMatch(w, r, cfg, true) {
+		tx.Rollback()
+		return
+	}
+	if !requireNoApproval(w, r, cfg) {
+		tx.Rollback()
 		return
 	}
 
@@ -152,88 +304,305 @@ func (c *ConfigController) Update(w http.ResponseWriter, r *http.Request) {
 		cfg.Config = req.Config
 	}
 
-	if err := config.DB.Save(&cfg).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to update config"))
+	if err := appendRevision(tx, cfg, userID, models.RevisionUpdated, nil); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to update config")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+
+	setConfigETag(w, cfg)
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config updated", cfg.ToResponse()))
+}
+
+// Patch handles PATCH /configs/{id} - apply a JSON Merge Patch (RFC 7386) to config_name and config
+func (c *ConfigController) Patch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+			respondError(w, r, models.ErrUnsupportedMediaType, "Use Content-Type: application/merge-patch+json")
+			return
+		}
+	}
+
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	for key := range patch {
+		if key != "config_name" && key != "config" {
+			respondError(w, r, models.ErrValidationFailed, "Only config_name and config can be patched",
+				models.FieldError{Field: key, Message: "cannot be patched"})
+			return
+		}
+	}
+	allowUnknown := r.URL.Query().Get("allow_unknown_keys") == "true"
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if !checkIfMatch(w, r, cfg, true) {
+		tx.Rollback()
+		return
+	}
+	if !requireNoApproval(w, r, cfg) {
+		tx.Rollback()
+		return
+	}
+
+	// Apply the patch to the editable document, then validate the result as a whole
+	document := map[string]interface{}{
+		"config_name": derefString(cfg.ConfigName),
+		"config":      map[string]interface{}(cfg.Config),
+	}
+	patched := models.MergePatch(document, patch).(map[string]interface{})
+
+	name, _ := patched["config_name"].(string)
+	if name == "" {
+		tx.Rollback()
+		respondError(w, r, models.ErrValidationFailed, "Config name is required", models.FieldError{Field: "config_name", Message: "is required"})
+		return
+	}
+	newConfig, ok := patched["config"].(map[string]interface{})
+	if !ok {
+		tx.Rollback()
+		respondError(w, r, models.ErrValidationFailed, "Config must be an object", models.FieldError{Field: "config", Message: "must be an object"})
+		return
+	}
+	if errs := models.ValidateConfig(newConfig, allowUnknown); len(errs) > 0 {
+		tx.Rollback()
+		respondError(w, r, models.ErrValidationFailed, "Config failed validation", errs...)
+		return
+	}
+
+	cfg.ConfigName = &name
+	cfg.Config = newConfig
+	if err := appendRevision(tx, cfg, userID, models.RevisionUpdated, nil); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to update config")
 		return
 	}
+	tx.Commit()
+	requestConfigSync()
 
+	setConfigETag(w, cfg)
 	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config updated", cfg.ToResponse()))
 }
 
-// Delete handles DELETE /configs/{id} - delete config
-func (c *ConfigController) Delete(w http.ResponseWriter, r *http.Request) {
+// Clone handles POST /configs/{id}/clone - copy a config, its labels and tenant overrides
+// into a new inactive config
+func (c *ConfigController) Clone(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	// The body is optional; without one the copy is named "<name> (copy)"
+	var req models.CloneConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	var source models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&source).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	name := req.ConfigName
+	if name == "" {
+		name = derefString(source.ConfigName) + " (copy)"
+	}
+	labels := source.Labels
+	if req.Labels != nil {
+		var errs []models.FieldError
+		if labels, errs = models.NormalizeLabels("labels", *req.Labels); len(errs) > 0 {
+			respondError(w, r, models.ErrValidationFailed, "Invalid labels", errs...)
+			return
+		}
+	}
+
+	// The copy keeps the approval policy and approvers, so cloning cannot be used
+	// to get an ungated copy of a gated config
+	cfg := models.GlobalJobConfig{
+		ConfigID:          uuid.New(),
+		UserID:            userID,
+		ConfigName:        &name,
+		Config:            source.Config,
+		Status:            models.ConfigStatusInactive,
+		Labels:            labels,
+		CreatedBy:         &userID,
+		RequiresApproval:  source.RequiresApproval,
+		RequiredApprovals: source.RequiredApprovals,
+	}
+
+	tx := config.DB.Begin()
+	var overrides []models.ConfigTenantOverride
+	if err := tx.Where("config_id = ?", source.ConfigID).Find(&overrides).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to clone config")
+		return
+	}
+	var approvers []models.ConfigApprover
+	if err := tx.Where("config_id = ?", source.ConfigID).Find(&approvers).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to clone config")
+		return
+	}
+	if err := tx.Create(&cfg).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to clone config")
+		return
+	}
+	sourceVersion := source.CurrentVersion
+	if err := appendRevision(tx, &cfg, userID, models.RevisionCloned, &sourceVersion); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to clone config")
+		return
+	}
+	now := time.Now()
+	for _, override := range overrides {
+		override.OverrideID = uuid.New()
+		override.ConfigID = cfg.ConfigID
+		override.CreatedBy = userID
+		override.CreatedAt = now
+		override.UpdatedAt = now
+		if err := tx.Create(&override).Error; err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to clone config")
+			return
+		}
+	}
+	for _, approver := range approvers {
+		approver.ConfigID = cfg.ConfigID
+		approver.AddedBy = userID
+		approver.CreatedAt = now
+		if err := tx.Create(&approver).Error; err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to clone config")
+			return
+		}
+	}
+	tx.Commit()
+
+	setConfigETag(w, &cfg)
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Config cloned", cfg.ToResponse()))
+}
+
+// SetLabels handles PUT /configs/{id}/labels - replace a config's labels.
+// Labels are metadata, so this does not create a revision.
+func (c *ConfigController) SetLabels(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	configID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid config ID"))
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
 		return
 	}
 
-	result := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).Delete(&models.GlobalJobConfig{})
-	if result.Error != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to delete config"))
+	var req models.SetLabelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
 		return
 	}
-	if result.RowsAffected == 0 {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Config not found"))
+	labels, errs := models.NormalizeLabels("labels", req.Labels)
+	if len(errs) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Invalid labels", errs...)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config deleted", nil))
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	cfg.Labels = labels
+	cfg.UpdatedAt = time.Now()
+	if err := tx.Model(cfg).Updates(map[string]interface{}{"labels": cfg.Labels, "updated_at": cfg.UpdatedAt}).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to update labels")
+		return
+	}
+	tx.Commit()
+
+	setConfigETag(w, cfg)
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Labels updated", cfg.ToResponse()))
 }
 
 // Activate handles POST /configs/{id}/activate - activate config
 func (c *ConfigController) Activate(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	configID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid config ID"))
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
 		return
 	}
 
 	// Start transaction
 	tx := config.DB.Begin()
 
-	// Deactivate all other configs for this user
-	if err := tx.Model(&models.GlobalJobConfig{}).
-		Where("user_id = ? AND status = ?", userID, models.ConfigStatusActive).
-		Update("status", models.ConfigStatusInactive).Error; err != nil {
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
 		tx.Rollback()
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to deactivate other configs"))
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
 		return
 	}
-
-	// Activate the specified config
-	result := tx.Model(&models.GlobalJobConfig{}).
-		Where("config_id = ? AND user_id = ?", configID, userID).
-		Update("status", models.ConfigStatusActive)
-	if result.Error != nil {
+	if !checkIfMatch(w, r, cfg, true) {
 		tx.Rollback()
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to activate config"))
 		return
 	}
-	if result.RowsAffected == 0 {
+	if !requireNoApproval(w, r, cfg) {
 		tx.Rollback()
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Config not found"))
 		return
 	}
 
-	tx.Commit()
+	if err := activateConfig(tx, cfg, manualActivation(userID)); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to activate config")
+		return
+	}
 
-	// Get updated config
-	var cfg models.GlobalJobConfig
-	config.DB.Where("config_id = ?", configID).First(&cfg)
+	tx.Commit()
+	requestConfigSync()
 
+	setConfigETag(w, cfg)
 	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config activated", cfg.ToResponse()))
 }
 
@@ -241,27 +610,34 @@ func (c *ConfigController) Activate(w http.ResponseWriter, r *http.Request) {
 func (c *ConfigController) Deactivate(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
 	configID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Invalid config ID"))
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
 		return
 	}
 
-	result := config.DB.Model(&models.GlobalJobConfig{}).
-		Where("config_id = ? AND user_id = ?", configID, userID).
-		Update("status", models.ConfigStatusInactive)
-	if result.Error != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to deactivate config"))
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
 		return
 	}
-	if result.RowsAffected == 0 {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Config not found"))
+	if !requireNoApproval(w, r, cfg) {
+		tx.Rollback()
 		return
 	}
+	if err := deactivateConfig(tx, cfg, manualActivation(userID)); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to deactivate config")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
 
 	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config deactivated", nil))
 }
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+)
+
+// configETag derives a strong ETag from the config's current revision
+func configETag(cfg *models.GlobalJobConfig) string {
+	if cfg.CurrentRevisionID == nil {
+		return `"v0"`
+	}
+	return `"` + cfg.CurrentRevisionID.String() + `"`
+}
+
+func setConfigETag(w http.ResponseWriter, cfg *models.GlobalJobConfig) {
+	w.Header().Set("ETag", configETag(cfg))
+}
+
+// checkIfMatch enforces If-Match against the config's ETag. It responds and returns
+// false when the precondition is missing (and required) or does not match.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, cfg *models.GlobalJobConfig, required bool) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		if !required {
+			return true
+		}
+		respondError(w, r, models.ErrPreconditionRequired, "If-Match header with the config ETag is required")
+		return false
+	}
+
+	if etagMatches(header, configETag(cfg)) {
+		return true
+	}
+	setConfigETag(w, cfg)
+	middleware.WriteErrorWithCurrent(w, r, models.ErrPreconditionFailed, "Config has been modified since it was read", cfg.ToResponse())
+	return false
+}
+
+// etagMatches reports whether a comma-separated If-Match/If-None-Match list matches etag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,327 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// maxImportBytes bounds the size of an import request
+const maxImportBytes = 10 << 20
+
+var fileNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// Export handles GET /configs/{id}/export?format=yaml|json - download a config as a file
+func (c *ConfigController) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" {
+		respondError(w, r, models.ErrInvalidQueryParam, "Unsupported export format", models.FieldError{Field: "format", Message: "must be yaml or json"})
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	// Both encoders emit map keys in sorted order, so exports diff cleanly in git
+	doc := models.ConfigDocument{Name: derefString(cfg.ConfigName), Config: cfg.Config}
+	var out bytes.Buffer
+	contentType := "application/json"
+	if format == "yaml" {
+		contentType = "application/yaml"
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(2)
+		err = enc.Encode(doc)
+		enc.Close()
+	} else {
+		enc := json.NewEncoder(&out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(doc)
+	}
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to export config")
+		return
+	}
+
+	fileName := strings.Trim(fileNameUnsafe.ReplaceAllString(strings.ToLower(doc.Name), "-"), "-")
+	if fileName == "" {
+		fileName = cfg.ConfigID.String()
+	}
+	setConfigETag(w, &cfg)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, fileName, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(out.Bytes())
+}
+
+// Import handles POST /configs/import - upsert one or many config documents by name.
+// With ?dry_run=true nothing is written and the response shows what would change.
+func (c *ConfigController) Import(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	allowUnknown := r.URL.Query().Get("allow_unknown_keys") == "true"
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = importFormat(r.Header.Get("Content-Type"))
+	}
+	if format != "yaml" && format != "json" {
+		respondError(w, r, models.ErrUnsupportedMediaType, "Send JSON or YAML (application/json or application/yaml)")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		respondError(w, r, models.ErrPayloadTooLarge, fmt.Sprintf("Import exceeds the %d byte limit", maxImportBytes))
+		return
+	}
+
+	docs, err := parseConfigDocuments(body, format)
+	if err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid import document: "+err.Error())
+		return
+	}
+	if len(docs) == 0 {
+		respondError(w, r, models.ErrValidationFailed, "No config documents found", models.FieldError{Field: "documents", Message: "must contain at least one document"})
+		return
+	}
+
+	// Validate every document before touching anything
+	var details []models.FieldError
+	seen := make(map[string]int, len(docs))
+	names := make([]string, 0, len(docs))
+	for i, doc := range docs {
+		prefix := fmt.Sprintf("documents[%d]", i)
+		if doc.Name == "" {
+			details = append(details, models.FieldError{Field: prefix + ".name", Message: "is required"})
+		} else if first, dup := seen[doc.Name]; dup {
+			details = append(details, models.FieldError{Field: prefix + ".name", Message: fmt.Sprintf("duplicates documents[%d]", first)})
+		} else {
+			seen[doc.Name] = i
+			names = append(names, doc.Name)
+		}
+		if doc.Config == nil {
+			details = append(details, models.FieldError{Field: prefix + ".config", Message: "is required"})
+			continue
+		}
+		for _, e := range models.ValidateConfig(doc.Config, allowUnknown) {
+			details = append(details, models.FieldError{Field: prefix + "." + e.Field, Message: e.Message})
+		}
+	}
+	if len(details) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Import failed validation", details...)
+		return
+	}
+
+	// Match documents to existing configs by name
+	var existing []models.GlobalJobConfig
+	if err := config.DB.Where("user_id = ? AND config_name IN ?", userID, names).Find(&existing).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch configs")
+		return
+	}
+	byName := make(map[string]models.GlobalJobConfig, len(existing))
+	for _, cfg := range existing {
+		name := derefString(cfg.ConfigName)
+		if _, dup := byName[name]; dup {
+			respondError(w, r, models.ErrValidationFailed, "Config name is ambiguous",
+				models.FieldError{Field: fmt.Sprintf("documents[%d].name", seen[name]), Message: "matches more than one existing config"})
+			return
+		}
+		byName[name] = cfg
+	}
+
+	resp := models.ImportResponse{DryRun: dryRun, Results: make([]models.ImportResult, len(docs))}
+	for i, doc := range docs {
+		result := models.ImportResult{Name: doc.Name, Action: models.ImportCreate}
+		if cfg, ok := byName[doc.Name]; ok {
+			id := cfg.ConfigID
+			result.ConfigID = &id
+			result.Version = cfg.CurrentVersion
+			result.Changes = models.DiffConfigs(cfg.Config, doc.Config)
+			result.Action = models.ImportUpdate
+			if len(result.Changes) == 0 {
+				result.Action = models.ImportUnchanged
+			} else if cfg.RequiresApproval {
+				details = append(details, models.FieldError{Field: fmt.Sprintf("documents[%d].name", i), Message: "config requires approval; open a change request"})
+			}
+		}
+		resp.Results[i] = result
+	}
+	if len(details) > 0 {
+		respondError(w, r, models.ErrApprovalRequired, "Import would change configs that require approval", details...)
+		return
+	}
+
+	if !dryRun {
+		tx := config.DB.Begin()
+		for i, doc := range docs {
+			result := &resp.Results[i]
+			name := doc.Name
+			switch result.Action {
+			case models.ImportCreate:
+				cfg := models.GlobalJobConfig{
+					ConfigID:   uuid.New(),
+					UserID:     userID,
+					ConfigName: &name,
+					Config:     doc.Config,
+					Status:     models.ConfigStatusInactive,
+					CreatedBy:  &userID,
+				}
+				if err := tx.Create(&cfg).Error; err == nil {
+					err = appendRevision(tx, &cfg, userID, models.RevisionCreated, nil)
+				}
+				if err != nil {
+					tx.Rollback()
+					respondError(w, r, models.ErrInternal, "Failed to create config "+name)
+					return
+				}
+				result.ConfigID = &cfg.ConfigID
+				result.Version = cfg.CurrentVersion
+			case models.ImportUpdate:
+				cfg, err := lockUserConfig(tx, userID, *result.ConfigID)
+				if err == nil {
+					cfg.Config = doc.Config
+					err = appendRevision(tx, cfg, userID, models.RevisionUpdated, nil)
+				}
+				if err != nil {
+					tx.Rollback()
+					respondError(w, r, models.ErrInternal, "Failed to update config "+name)
+					return
+				}
+				result.Version = cfg.CurrentVersion
+			}
+		}
+		tx.Commit()
+		requestConfigSync()
+	}
+
+	for _, result := range resp.Results {
+		switch result.Action {
+		case models.ImportCreate:
+			resp.Created++
+		case models.ImportUpdate:
+			resp.Updated++
+		default:
+			resp.Unchanged++
+		}
+	}
+
+	message := "Configs imported"
+	if dryRun {
+		message = "Import dry run"
+	}
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse(message, resp))
+}
+
+// importFormat maps a Content-Type to an import format
+func importFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return "yaml"
+	case "", "application/json":
+		return "json"
+	}
+	return ""
+}
+
+// parseConfigDocuments accepts a single document, a list of documents, or (for YAML)
+// several "---" separated documents
+func parseConfigDocuments(body []byte, format string) ([]models.ConfigDocument, error) {
+	var raw []interface{}
+	if format == "yaml" {
+		dec := yaml.NewDecoder(bytes.NewReader(body))
+		for {
+			var v interface{}
+			err := dec.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			raw = append(raw, v)
+		}
+	} else {
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return nil, err
+		}
+		raw = append(raw, v)
+	}
+
+	var docs []models.ConfigDocument
+	for _, v := range raw {
+		if list, ok := v.([]interface{}); ok {
+			for _, item := range list {
+				doc, err := toConfigDocument(item)
+				if err != nil {
+					return nil, err
+				}
+				docs = append(docs, doc)
+			}
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		doc, err := toConfigDocument(v)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// toConfigDocument round-trips a decoded value through JSON, so YAML and JSON
+// documents end up with the same value types (numbers as float64) for validation
+func toConfigDocument(v interface{}) (models.ConfigDocument, error) {
+	var doc models.ConfigDocument
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return doc, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,128}$`)
+
+// Overrides handles GET /configs/{id}/overrides - list tenant overrides of a config
+func (c *ConfigController) Overrides(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	var overrides []models.ConfigTenantOverride
+	if err := config.DB.Where("config_id = ?", configID).Order("tenant_id").Find(&overrides).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch overrides")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Overrides retrieved", overrides))
+}
+
+// GetOverride handles GET /configs/{id}/overrides/{tenantId} - get one tenant's override
+func (c *ConfigController) GetOverride(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	override, err := findOverride(config.DB, configID, chi.URLParam(r, "tenantId"))
+	if err != nil {
+		respondError(w, r, models.ErrOverrideNotFound, "No override for this tenant")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Override retrieved", override))
+}
+
+// SetOverride handles PUT /configs/{id}/overrides/{tenantId} - create or replace a tenant override
+func (c *ConfigController) SetOverride(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	tenantID := chi.URLParam(r, "tenantId")
+	if !tenantIDPattern.MatchString(tenantID) {
+		respondError(w, r, models.ErrValidationFailed, "Invalid tenant ID", models.FieldError{Field: "tenant_id", Message: "must be 1-128 letters, digits, '_', '.', ':' or '-'"})
+		return
+	}
+
+	var req models.SetOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if len(req.Overrides) == 0 {
+		respondError(w, r, models.ErrValidationFailed, "Overrides are required", models.FieldError{Field: "overrides", Message: "must set at least one key"})
+		return
+	}
+	if errs := validateOverrides(req.Overrides, req.AllowUnknownKeys); len(errs) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Invalid overrides", errs...)
+		return
+	}
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if !requireNoApproval(w, r, cfg) {
+		tx.Rollback()
+		return
+	}
+
+	now := time.Now()
+	status := http.StatusOK
+	override, err := findOverride(tx, configID, tenantID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		status = http.StatusCreated
+		override = &models.ConfigTenantOverride{
+			OverrideID: uuid.New(),
+			ConfigID:   configID,
+			TenantID:   tenantID,
+			CreatedBy:  userID,
+			CreatedAt:  now,
+		}
+	} else if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to fetch override")
+		return
+	}
+	override.Overrides = req.Overrides
+	override.UpdatedAt = now
+
+	if err := tx.Save(override).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to save override")
+		return
+	}
+	if err := queueOverrideSync(tx, cfg); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to save override")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+
+	respondJSON(w, status, models.NewSuccessResponse("Override saved", override))
+}
+
+// DeleteOverride handles DELETE /configs/{id}/overrides/{tenantId} - remove a tenant override
+func (c *ConfigController) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if !requireNoApproval(w, r, cfg) {
+		tx.Rollback()
+		return
+	}
+
+	result := tx.Where("config_id = ? AND tenant_id = ?", configID, chi.URLParam(r, "tenantId")).Delete(&models.ConfigTenantOverride{})
+	if result.Error != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to delete override")
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		respondError(w, r, models.ErrOverrideNotFound, "No override for this tenant")
+		return
+	}
+	if err := queueOverrideSync(tx, cfg); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to delete override")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Override deleted", nil))
+}
+
+// Effective handles GET /configs/{id}/effective?tenant_id= - the config as applied to a tenant
+func (c *ConfigController) Effective(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	tenantID := r.URL.Query().Get("tenant_id")
+	if tenantID == "" {
+		respondError(w, r, models.ErrInvalidQueryParam, "tenant_id is required", models.FieldError{Field: "tenant_id", Message: "is required"})
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	var overrides map[string]interface{}
+	override, err := findOverride(config.DB, configID, tenantID)
+	if err == nil {
+		overrides = override.Overrides
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(w, r, models.ErrInternal, "Failed to fetch override")
+		return
+	}
+
+	effective, sources := models.ResolveEffectiveConfig(cfg.Config, overrides)
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Effective config retrieved", models.EffectiveConfigResponse{
+		ConfigID: cfg.ConfigID,
+		TenantID: tenantID,
+		Version:  cfg.CurrentVersion,
+		Config:   effective,
+		Sources:  sources,
+	}))
+}
+
+func findOverride(db *gorm.DB, configID uuid.UUID, tenantID string) (*models.ConfigTenantOverride, error) {
+	var override models.ConfigTenantOverride
+	if err := db.Where("config_id = ? AND tenant_id = ?", configID, tenantID).First(&override).Error; err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// loadOverrides returns every tenant override of a config keyed by tenant
+func loadOverrides(db *gorm.DB, configID uuid.UUID) (map[string]map[string]interface{}, error) {
+	var overrides []models.ConfigTenantOverride
+	if err := db.Where("config_id = ?", configID).Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+	byTenant := make(map[string]map[string]interface{}, len(overrides))
+	for _, o := range overrides {
+		byTenant[o.TenantID] = o.Overrides
+	}
+	return byTenant, nil
+}
+
+// validateOverrides applies the base config rules, reporting paths under "overrides."
+func validateOverrides(overrides map[string]interface{}, allowUnknown bool) []models.FieldError {
+	errs := models.ValidateConfig(overrides, allowUnknown)
+	for i := range errs {
+		errs[i].Field = "overrides." + strings.TrimPrefix(errs[i].Field, "config.")
+	}
+	return errs
+}
+
+// queueOverrideSync re-sends the active config to Janus after its overrides change
+func queueOverrideSync(tx *gorm.DB, cfg *models.GlobalJobConfig) error {
+	if cfg.Status != models.ConfigStatusActive {
+		return nil
+	}
+	markSyncPending(cfg)
+	return tx.Save(cfg).Error
+}
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"janus-backend-api/admission"
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSimulationRange = 7 * 24 * time.Hour
+	maxSimulationRange     = 31 * 24 * time.Hour
+	maxSimulationJobs      = 500000
+	defaultMaxFlipped      = 100
+	maxFlippedLimit        = 1000
+)
+
+// Simulate handles POST /configs/{id}/simulate - replay historical jobs against a config.
+// Jobs in the same batch are treated as concurrent; unbatched jobs run alone.
+func (c *ConfigController) Simulate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	// The body is optional; without one the last week is replayed
+	var req models.SimulateConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	to := time.Now().UTC()
+	if req.To != nil {
+		to = req.To.UTC()
+	}
+	from := to.Add(-defaultSimulationRange)
+	if req.From != nil {
+		from = req.From.UTC()
+	}
+	if !from.Before(to) {
+		respondError(w, r, models.ErrValidationFailed, "Invalid time range", models.FieldError{Field: "from", Message: "must be before to"})
+		return
+	}
+	if to.Sub(from) > maxSimulationRange {
+		respondError(w, r, models.ErrValidationFailed, "Invalid time range", models.FieldError{Field: "from", Message: "range must not exceed 31 days"})
+		return
+	}
+	maxFlipped := req.MaxFlipped
+	if maxFlipped <= 0 {
+		maxFlipped = defaultMaxFlipped
+	}
+	if maxFlipped > maxFlippedLimit {
+		maxFlipped = maxFlippedLimit
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	// Tenants with overrides are admitted under their effective config
+	admitter := admission.NewAdmitter(models.ParseConfigSpec(cfg.Config))
+	overrides, err := loadOverrides(config.DB, cfg.ConfigID)
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch overrides")
+		return
+	}
+	for tenantID, override := range overrides {
+		effective, _ := models.ResolveEffectiveConfig(cfg.Config, override)
+		admitter.SetTenantSpec(tenantID, models.ParseConfigSpec(effective))
+	}
+
+	// Order by batch so each concurrent group is contiguous
+	rows, err := config.DB.Model(&models.Job{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Order("COALESCE(batch_id, job_id), created_at, job_id").
+		Limit(maxSimulationJobs + 1).
+		Rows()
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch jobs")
+		return
+	}
+	defer rows.Close()
+
+	result := models.SimulationResult{
+		ConfigID:     cfg.ConfigID,
+		Version:      cfg.CurrentVersion,
+		From:         from,
+		To:           to,
+		ByReason:     map[string]int{},
+		ByDependency: map[string]int{},
+		Flipped:      []models.FlippedJob{},
+	}
+	tenants := map[string]*models.TenantSimulation{}
+	group := ""
+
+	for rows.Next() {
+		if result.TotalJobs == maxSimulationJobs {
+			result.Truncated = true
+			break
+		}
+		var job models.Job
+		if err := config.DB.ScanRows(rows, &job); err != nil {
+			respondError(w, r, models.ErrInternal, "Failed to read jobs")
+			return
+		}
+		result.TotalJobs++
+
+		jobGroup := job.JobID
+		if job.BatchID != nil {
+			jobGroup = *job.BatchID
+		}
+		if jobGroup != group {
+			admitter.Reset()
+			group = jobGroup
+		}
+
+		candidate := admission.JobFromPayload(job.JobPayload)
+		decision := admitter.Admit(candidate)
+
+		tenant := tenants[candidate.TenantID]
+		if tenant == nil {
+			tenant = &models.TenantSimulation{TenantID: candidate.TenantID}
+			tenants[candidate.TenantID] = tenant
+		}
+		tenant.Jobs++
+
+		projected := "accepted"
+		if decision.Accepted {
+			result.Projected.Accepted++
+			tenant.Projected.Accepted++
+		} else {
+			projected = "rejected"
+			result.Projected.Rejected++
+			tenant.Projected.Rejected++
+			result.ByReason[decision.Reason]++
+			if decision.Dependency != "" {
+				result.ByDependency[decision.Dependency]++
+			}
+		}
+
+		// Jobs that are neither accepted nor rejected yet cannot flip
+		switch job.JobStatus {
+		case "accepted":
+			result.Actual.Accepted++
+			tenant.Actual.Accepted++
+		case "rejected":
+			result.Actual.Rejected++
+			tenant.Actual.Rejected++
+		default:
+			continue
+		}
+		if job.JobStatus == projected {
+			continue
+		}
+
+		if decision.Accepted {
+			result.NewlyAccepted++
+		} else {
+			result.NewlyRejected++
+		}
+		if len(result.Flipped) == maxFlipped {
+			result.FlippedTruncated = true
+			continue
+		}
+		flipped := models.FlippedJob{
+			JobID:           job.JobID,
+			TenantID:        candidate.TenantID,
+			Priority:        candidate.Priority,
+			CreatedAt:       job.CreatedAt,
+			ActualStatus:    job.JobStatus,
+			ActualReason:    derefString(job.Reason),
+			ProjectedStatus: projected,
+			ProjectedReason: decision.Reason,
+		}
+		if job.BatchID != nil {
+			flipped.BatchID = *job.BatchID
+		}
+		result.Flipped = append(result.Flipped, flipped)
+	}
+	if err := rows.Err(); err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to read jobs")
+		return
+	}
+
+	result.ByTenant = make([]models.TenantSimulation, 0, len(tenants))
+	for _, tenant := range tenants {
+		result.ByTenant = append(result.ByTenant, *tenant)
+	}
+	sort.Slice(result.ByTenant, func(i, j int) bool {
+		return result.ByTenant[i].TenantID < result.ByTenant[j].TenantID
+	})
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Simulation complete", result))
+}
@@ -0,0 +1,496 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+	"janus-backend-api/signing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// janusConfigPath is the Janus endpoint that holds a user's active config
+	janusConfigPath   = "/dashboard/config"
+	configSyncTimeout = 15 * time.Second
+	maxSyncBackoff    = 30 * time.Minute
+	syncBatchSize     = 50
+)
+
+// errJanusConfigUnsupported means Janus cannot report its config, so drift cannot be checked
+var errJanusConfigUnsupported = errors.New("janus does not report its config")
+
+// configSyncWake wakes the sync worker as soon as a config becomes pending
+var configSyncWake = make(chan struct{}, 1)
+
+// requestConfigSync asks the sync worker to run now instead of at its next tick
+func requestConfigSync() {
+	select {
+	case configSyncWake <- struct{}{}:
+	default:
+	}
+}
+
+// markSyncPending flags cfg for delivery to Janus; the caller saves cfg
+func markSyncPending(cfg *models.GlobalJobConfig) {
+	status := models.SyncPending
+	cfg.SyncStatus = &status
+	cfg.SyncError = nil
+	cfg.SyncAttempts = 0
+	cfg.NextSyncAt = nil
+}
+
+// Sync handles POST /configs/{id}/sync - push the active config to Janus again now
+func (c *ConfigController) Sync(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if cfg.Status != models.ConfigStatusActive {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotActive, "Only the active config is pushed to Janus")
+		return
+	}
+
+	markSyncPending(cfg)
+	if err := tx.Save(cfg).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to queue config sync")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+
+	respondJSON(w, http.StatusAccepted, models.NewSuccessResponse("Config sync queued", cfg.ToResponse()))
+}
+
+// janusConfigPush is the body sent to Janus
+type janusConfigPush struct {
+	ConfigID        uuid.UUID                         `json:"config_id"`
+	RevisionID      *uuid.UUID                        `json:"revision_id"`
+	Version         int                               `json:"version"`
+	Config          map[string]interface{}            `json:"config"`
+	TenantOverrides map[string]map[string]interface{} `json:"tenant_overrides"`
+}
+
+// janusConfigState is what Janus reports as the config it is enforcing
+type janusConfigState struct {
+	ConfigID   *uuid.UUID `json:"config_id"`
+	RevisionID *uuid.UUID `json:"revision_id"`
+	Version    int        `json:"version"`
+}
+
+// ConfigSyncer pushes active configs to Janus, tells it when a user has none
+// left, retries failed pushes with backoff, and periodically checks that Janus
+// still enforces the active config, or none
+type ConfigSyncer struct {
+	janusBaseURL      string
+	httpClient        *http.Client
+	signer            *signing.Signer
+	interval          time.Duration
+	reconcileInterval time.Duration
+}
+
+// NewConfigSyncer creates a ConfigSyncer from the app configuration; a zero
+// reconcile interval turns drift checks off
+func NewConfigSyncer(cfg *config.AppConfig) *ConfigSyncer {
+	interval := cfg.ConfigSyncInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	return &ConfigSyncer{
+		janusBaseURL:      strings.TrimRight(cfg.JanusBaseURL, "/"),
+		httpClient:        &http.Client{Timeout: configSyncTimeout},
+		signer:            newJanusSigner(cfg),
+		interval:          interval,
+		reconcileInterval: cfg.ConfigReconcileInterval,
+	}
+}
+
+// Run delivers pending configs and reconciles with Janus until ctx is cancelled
+func (s *ConfigSyncer) Run(ctx context.Context) {
+	if s.janusBaseURL == "" {
+		log.Println("⚠️  JANUS_BASE_URL not set - configs will not be pushed to Janus")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	var reconcile <-chan time.Time
+	if s.reconcileInterval > 0 {
+		reconcileTicker := time.NewTicker(s.reconcileInterval)
+		defer reconcileTicker.Stop()
+		reconcile = reconcileTicker.C
+	}
+
+	for {
+		s.pushDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-configSyncWake:
+		case <-reconcile:
+			s.reconcile(ctx)
+		}
+	}
+}
+
+// pushDue pushes active configs and clears that are pending, or failed and due for a retry
+func (s *ConfigSyncer) pushDue(ctx context.Context) {
+	var configs []models.GlobalJobConfig
+	err := config.DB.
+		Where("status = ? AND sync_status IN ?", models.ConfigStatusActive, []string{models.SyncPending, models.SyncFailed}).
+		Where("next_sync_at IS NULL OR next_sync_at <= ?", time.Now()).
+		Limit(syncBatchSize).
+		Find(&configs).Error
+	if err != nil {
+		log.Printf("Config sync: failed to list pending configs: %v", err)
+		return
+	}
+
+	for i := range configs {
+		if ctx.Err() != nil {
+			return
+		}
+		s.pushOne(ctx, &configs[i])
+	}
+
+	var clears []models.JanusConfigClear
+	err = config.DB.
+		Where("next_sync_at IS NULL OR next_sync_at <= ?", time.Now()).
+		Limit(syncBatchSize).
+		Find(&clears).Error
+	if err != nil {
+		log.Printf("Config sync: failed to list pending clears: %v", err)
+		return
+	}
+
+	for i := range clears {
+		if ctx.Err() != nil {
+			return
+		}
+		s.clearOne(ctx, &clears[i])
+	}
+}
+
+// pushOne pushes cfg and records the outcome. The outcome only sticks if cfg has not
+// changed meanwhile; a newer revision stays pending and is pushed next.
+func (s *ConfigSyncer) pushOne(ctx context.Context, cfg *models.GlobalJobConfig) {
+	err := s.push(ctx, cfg)
+	now := time.Now()
+
+	var updates map[string]interface{}
+	if err == nil {
+		updates = map[string]interface{}{
+			"sync_status":        models.SyncSynced,
+			"sync_error":         nil,
+			"sync_attempts":      0,
+			"next_sync_at":       nil,
+			"synced_at":          now,
+			"synced_revision_id": cfg.CurrentRevisionID,
+			"drift_detected_at":  nil,
+		}
+	} else {
+		attempts := cfg.SyncAttempts + 1
+		log.Printf("Config sync: push of config %s failed (attempt %d): %v", cfg.ConfigID, attempts, err)
+		updates = map[string]interface{}{
+			"sync_status":   models.SyncFailed,
+			"sync_error":    err.Error(),
+			"sync_attempts": attempts,
+			"next_sync_at":  now.Add(syncBackoff(s.interval, attempts)),
+		}
+	}
+
+	query := config.DB.Model(&models.GlobalJobConfig{}).
+		Where("config_id = ? AND status = ? AND current_version = ?", cfg.ConfigID, models.ConfigStatusActive, cfg.CurrentVersion)
+	if err := query.Updates(updates).Error; err != nil {
+		log.Printf("Config sync: failed to record sync state of config %s: %v", cfg.ConfigID, err)
+	}
+}
+
+// clearOne tells Janus that clear.UserID has no active config and records the
+// outcome, unless the clear was queued again or replaced by an activation meanwhile
+func (s *ConfigSyncer) clearOne(ctx context.Context, clear *models.JanusConfigClear) {
+	query := config.DB.Where("user_id = ? AND queued_at = ?", clear.UserID, clear.QueuedAt)
+
+	// A config activated since then is pushed instead
+	var active int64
+	config.DB.Model(&models.GlobalJobConfig{}).Where("user_id = ? AND status = ?", clear.UserID, models.ConfigStatusActive).Count(&active)
+	if active > 0 {
+		query.Delete(&models.JanusConfigClear{})
+		return
+	}
+
+	err := s.clear(ctx, clear.UserID)
+
+	if err == nil {
+		if err := query.Delete(&models.JanusConfigClear{}).Error; err != nil {
+			log.Printf("Config sync: failed to record clear for user %s: %v", clear.UserID, err)
+		}
+		return
+	}
+
+	attempts := clear.Attempts + 1
+	log.Printf("Config sync: clear for user %s failed (attempt %d): %v", clear.UserID, attempts, err)
+	err = query.Model(&models.JanusConfigClear{}).Updates(map[string]interface{}{
+		"attempts":     attempts,
+		"last_error":   err.Error(),
+		"next_sync_at": time.Now().Add(syncBackoff(s.interval, attempts)),
+	}).Error
+	if err != nil {
+		log.Printf("Config sync: failed to record clear for user %s: %v", clear.UserID, err)
+	}
+}
+
+// clear removes userID's config from Janus; Janus not having one counts as done
+func (s *ConfigSyncer) clear(ctx context.Context, userID uuid.UUID) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, userID, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("janus returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// push sends cfg to Janus
+func (s *ConfigSyncer) push(ctx context.Context, cfg *models.GlobalJobConfig) error {
+	overrides, err := loadOverrides(config.DB, cfg.ConfigID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(janusConfigPush{
+		ConfigID:        cfg.ConfigID,
+		RevisionID:      cfg.CurrentRevisionID,
+		Version:         cfg.CurrentVersion,
+		Config:          cfg.Config,
+		TenantOverrides: overrides,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, cfg.UserID, body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("janus returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// fetch asks Janus which config it enforces for userID; nil means none
+func (s *ConfigSyncer) fetch(ctx context.Context, userID uuid.UUID) (*janusConfigState, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return nil, errJanusConfigUnsupported
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("janus returned %d", resp.StatusCode)
+	}
+
+	var state janusConfigState
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *ConfigSyncer) newRequest(ctx context.Context, method string, userID uuid.UUID, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.janusBaseURL+janusConfigPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set(signing.HeaderUserID, userID.String())
+	if s.signer != nil {
+		if err := s.signer.Sign(req, signing.BodyDigest(body)); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// reconcile compares every synced active config with what Janus reports and
+// queues a re-push where they differ, then does the same for users left without
+// an active config
+func (s *ConfigSyncer) reconcile(ctx context.Context) {
+	var configs []models.GlobalJobConfig
+	err := config.DB.Where("status = ? AND sync_status = ?", models.ConfigStatusActive, models.SyncSynced).Find(&configs).Error
+	if err != nil {
+		log.Printf("Config sync: failed to list synced configs: %v", err)
+		return
+	}
+
+	for _, cfg := range configs {
+		if ctx.Err() != nil {
+			return
+		}
+		state, err := s.fetch(ctx, cfg.UserID)
+		if errors.Is(err, errJanusConfigUnsupported) {
+			log.Printf("Config sync: %v, skipping drift check", err)
+			return
+		}
+		if err != nil {
+			log.Printf("Config sync: failed to fetch Janus config for user %s: %v", cfg.UserID, err)
+			continue
+		}
+
+		drift := describeDrift(&cfg, state)
+		if drift == "" {
+			continue
+		}
+		log.Printf("Config sync: drift on config %s: %s", cfg.ConfigID, drift)
+		config.DB.Model(&models.GlobalJobConfig{}).
+			Where("config_id = ? AND current_version = ? AND sync_status = ?", cfg.ConfigID, cfg.CurrentVersion, models.SyncSynced).
+			Updates(map[string]interface{}{
+				"sync_status":       models.SyncPending,
+				"sync_error":        "drift: " + drift,
+				"sync_attempts":     0,
+				"next_sync_at":      nil,
+				"drift_detected_at": time.Now(),
+			})
+		requestConfigSync()
+	}
+
+	s.reconcileCleared(ctx)
+}
+
+// reconcileCleared checks users whose configs reached Janus but who have no
+// active config and no pending clear, and queues a clear where Janus still
+// enforces one. Deleted configs count, since force delete deactivates.
+func (s *ConfigSyncer) reconcileCleared(ctx context.Context) {
+	var userIDs []uuid.UUID
+	err := config.DB.Raw(`SELECT DISTINCT g.user_id FROM global_job_config g
+		WHERE g.synced_at IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM global_job_config a WHERE a.user_id = g.user_id AND a.status = ? AND a.deleted_at IS NULL)
+			AND NOT EXISTS (SELECT 1 FROM janus_config_clears c WHERE c.user_id = g.user_id)`, models.ConfigStatusActive).
+		Scan(&userIDs).Error
+	if err != nil {
+		log.Printf("Config sync: failed to list users without an active config: %v", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		state, err := s.fetch(ctx, userID)
+		if errors.Is(err, errJanusConfigUnsupported) {
+			log.Printf("Config sync: %v, skipping drift check", err)
+			return
+		}
+		if err != nil {
+			log.Printf("Config sync: failed to fetch Janus config for user %s: %v", userID, err)
+			continue
+		}
+		if state == nil || state.ConfigID == nil {
+			continue
+		}
+
+		drift := fmt.Sprintf("drift: janus enforces config %s, user has no active config", state.ConfigID)
+		log.Printf("Config sync: %s", drift)
+		// An activation in the meantime has already queued a push instead
+		tx := config.DB.Begin()
+		var active int64
+		tx.Model(&models.GlobalJobConfig{}).Where("user_id = ? AND status = ?", userID, models.ConfigStatusActive).Count(&active)
+		if active > 0 {
+			tx.Rollback()
+			continue
+		}
+		if err := queueConfigClear(tx, userID, &drift); err != nil {
+			tx.Rollback()
+			log.Printf("Config sync: failed to queue clear for user %s: %v", userID, err)
+			continue
+		}
+		tx.Commit()
+		requestConfigSync()
+	}
+}
+
+// describeDrift explains how Janus' state differs from cfg, or returns ""
+func describeDrift(cfg *models.GlobalJobConfig, state *janusConfigState) string {
+	if state == nil || state.ConfigID == nil {
+		return "janus has no active config"
+	}
+	if *state.ConfigID != cfg.ConfigID {
+		return fmt.Sprintf("janus enforces config %s", state.ConfigID)
+	}
+	if state.RevisionID != nil && cfg.CurrentRevisionID != nil && *state.RevisionID != *cfg.CurrentRevisionID {
+		return fmt.Sprintf("janus enforces version %d, current is %d", state.Version, cfg.CurrentVersion)
+	}
+	if state.RevisionID == nil && state.Version != cfg.CurrentVersion {
+		return fmt.Sprintf("janus enforces version %d, current is %d", state.Version, cfg.CurrentVersion)
+	}
+	return ""
+}
+
+// syncBackoff doubles the delay with every failed attempt, up to maxSyncBackoff
+func syncBackoff(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxSyncBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxSyncBackoff {
+		delay = maxSyncBackoff
+	}
+	return delay
+}
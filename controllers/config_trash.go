@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// configPurgeInterval is how often the trash is checked for expired configs
+const configPurgeInterval = time.Hour
+
+// Delete handles DELETE /configs/{id} - move a config to the trash.
+// The active config is only deleted with ?force=true, which deactivates it first.
+func (c *ConfigController) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	force := r.URL.Query().Get("force") == "true"
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if !checkIfMatch(w, r, cfg, true) {
+		tx.Rollback()
+		return
+	}
+	if cfg.Status == models.ConfigStatusActive {
+		if !force {
+			tx.Rollback()
+			respondError(w, r, models.ErrConfigActive, "Config is active; deactivate it first or delete with ?force=true")
+			return
+		}
+		if !requireNoApproval(w, r, cfg) {
+			tx.Rollback()
+			return
+		}
+		if err := deactivateConfig(tx, cfg, manualActivation(userID)); err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to delete config")
+			return
+		}
+	}
+
+	if err := trashConfig(tx, cfg, userID, time.Now()); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to delete config")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config moved to trash", c.trashedResponse(cfg)))
+}
+
+// Trash handles GET /configs/trash - list deleted configs that can still be restored
+func (c *ConfigController) Trash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := config.DB.Unscoped().Model(&models.GlobalJobConfig{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	var total int64
+	query.Count(&total)
+
+	var configs []models.GlobalJobConfig
+	offset := (page - 1) * perPage
+	if err := query.Order("deleted_at DESC").Offset(offset).Limit(perPage).Find(&configs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch deleted configs")
+		return
+	}
+
+	responses := make([]models.TrashedConfigResponse, len(configs))
+	for i := range configs {
+		responses[i] = c.trashedResponse(&configs[i])
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(responses, page, perPage, total))
+}
+
+// Restore handles POST /configs/{id}/restore - take a config out of the trash.
+// It comes back inactive; schedules disabled by the delete stay disabled.
+func (c *ConfigController) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	tx := config.DB.Begin()
+	var cfg models.GlobalJobConfig
+	err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("config_id = ? AND user_id = ? AND deleted_at IS NOT NULL", configID, userID).
+		First(&cfg).Error
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found in trash")
+		return
+	}
+
+	if err := tx.Unscoped().Model(&cfg).Update("deleted_at", nil).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to restore config")
+		return
+	}
+	tx.Commit()
+
+	cfg.DeletedAt = gorm.DeletedAt{}
+	setConfigETag(w, &cfg)
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config restored", cfg.ToResponse()))
+}
+
+func (c *ConfigController) trashedResponse(cfg *models.GlobalJobConfig) models.TrashedConfigResponse {
+	return models.TrashedConfigResponse{
+		ConfigResponse: cfg.ToResponse(),
+		PurgeAt:        cfg.DeletedAt.Time.Add(c.trashRetention),
+	}
+}
+
+// trashConfig soft-deletes cfg, disables schedules that would switch to it and
+// cancels its open change requests. cfg must be locked and inactive in tx.
+func trashConfig(tx *gorm.DB, cfg *models.GlobalJobConfig, userID uuid.UUID, now time.Time) error {
+	if err := tx.Model(cfg).Update("deleted_at", now).Error; err != nil {
+		return err
+	}
+	cfg.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+
+	if err := tx.Model(&models.ConfigSchedule{}).
+		Where("enabled AND (config_id = ? OR fallback_config_id = ?)", cfg.ConfigID, cfg.ConfigID).
+		Updates(map[string]interface{}{"enabled": false, "last_error": "config deleted"}).Error; err != nil {
+		return err
+	}
+
+	var open []models.ChangeRequest
+	if err := tx.Where("config_id = ? AND status = ?", cfg.ConfigID, models.ChangeOpen).Find(&open).Error; err != nil {
+		return err
+	}
+	for i := range open {
+		if err := closeChangeRequest(tx, &open[i], userID, models.ChangeCancelled, models.ChangeEventCancelled, "config deleted"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigPurger permanently removes configs that have been in the trash longer
+// than the retention period. Revisions, activation history and change requests
+// are kept so jobs and audit trails still resolve.
+type ConfigPurger struct {
+	retention time.Duration
+}
+
+// NewConfigPurger creates a ConfigPurger that purges configs deleted more than retention ago
+func NewConfigPurger(retention time.Duration) *ConfigPurger {
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	return &ConfigPurger{retention: retention}
+}
+
+// Run purges expired configs until ctx is cancelled
+func (p *ConfigPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(configPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ConfigPurger) purge(now time.Time) {
+	cutoff := now.Add(-p.retention)
+
+	var ids []uuid.UUID
+	if err := config.DB.Unscoped().Model(&models.GlobalJobConfig{}).
+		Where("deleted_at < ?", cutoff).
+		Pluck("config_id", &ids).Error; err != nil {
+		log.Printf("Trash: failed to list expired configs: %v", err)
+		return
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := p.purgeConfig(id, cutoff); err != nil {
+			log.Printf("Trash: failed to purge config %s: %v", id, err)
+			continue
+		}
+		purged++
+	}
+	if purged > 0 {
+		log.Printf("Trash: purged %d config(s)", purged)
+	}
+}
+
+// purgeConfig deletes one expired config and the rows that only exist for it.
+// Configs locked by another replica, or restored meanwhile, are skipped.
+func (p *ConfigPurger) purgeConfig(configID uuid.UUID, cutoff time.Time) error {
+	tx := config.DB.Begin()
+
+	var cfg models.GlobalJobConfig
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("config_id = ? AND deleted_at < ?", configID, cutoff).
+		First(&cfg).Error
+	if err != nil {
+		tx.Rollback()
+		return nil
+	}
+
+	if err := tx.Where("config_id = ?", configID).Delete(&models.ConfigTenantOverride{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("config_id = ? OR fallback_config_id = ?", configID, configID).Delete(&models.ConfigSchedule{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("config_id = ?", configID).Delete(&models.ConfigApprover{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("config_id = ?", configID).Delete(&models.ConfigShare{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&models.ConfigTemplate{}).Where("source_config_id = ?", configID).Update("source_config_id", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Unscoped().Delete(&cfg).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
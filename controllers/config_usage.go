@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// usageColumns counts a config's jobs straight from the jobs table. Janus keeps
+// its own counters in user_association and what they count is not confirmed, so
+// usage is never derived from or written back to them.
+const usageColumns = `COUNT(DISTINCT batch_id) AS batches,
+	COUNT(*) FILTER (WHERE batch_id IS NULL) AS single_jobs,
+	COUNT(*) FILTER (WHERE job_status = 'accepted') AS accepted_jobs,
+	COUNT(*) FILTER (WHERE job_status = 'rejected') AS rejected_jobs,
+	COUNT(*) AS total_jobs`
+
+// usageDiffers is true when a stored row s disagrees with the counts a; missing
+// counters count as zero. Janus has not confirmed what its counters count, so
+// this mapping is an assumption: batches for no_of_batches, jobs with batch_id
+// IS NULL for no_of_jobs and accepted and rejected jobs for succeeded and failed
+// jobs. If no_of_jobs turns out to include batched jobs, every config that ran
+// a batch is reported as drifted.
+const usageDiffers = `(COALESCE(s.no_of_batches, 0), COALESCE(s.no_of_jobs, 0), COALESCE(s.succeeded_jobs, 0), COALESCE(s.failed_jobs, 0), COALESCE(s.total_jobs, 0))
+	IS DISTINCT FROM (COALESCE(a.batches, 0), COALESCE(a.single_jobs, 0), COALESCE(a.accepted_jobs, 0), COALESCE(a.rejected_jobs, 0), COALESCE(a.total_jobs, 0))`
+
+// Usage handles GET /configs/{id}/usage - get how often a config has been used
+func (c *ConfigController) Usage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	usage, err := configUsage([]uuid.UUID{configID})
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch config usage")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config usage retrieved", usage[configID]))
+}
+
+// UsageCheck handles GET /configs/usage/check - compare the user_association
+// counters of the user's configs with their jobs and report any drift. Nothing
+// is corrected.
+func (c *ConfigController) UsageCheck(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Deleted configs are included; their jobs and counters are still there
+	var rows []struct {
+		ConfigID        uuid.UUID
+		UserID          uuid.UUID
+		StoredBatches   int64
+		StoredJobs      int64
+		StoredSucceeded int64
+		StoredFailed    int64
+		StoredTotal     int64
+		ActualBatches   int64
+		ActualSingle    int64
+		ActualAccepted  int64
+		ActualRejected  int64
+		ActualTotal     int64
+	}
+	err := config.DB.Raw(`WITH owned AS (SELECT config_id FROM global_job_config WHERE user_id = ?),
+		a AS (SELECT global_config_id AS config_id, user_id, `+usageColumns+`
+			FROM jobs WHERE global_config_id IN (SELECT config_id FROM owned) GROUP BY 1, 2),
+		s AS (SELECT config_id, user_id, SUM(no_of_batches) AS no_of_batches, SUM(no_of_jobs) AS no_of_jobs,
+			SUM(succeeded_jobs) AS succeeded_jobs, SUM(failed_jobs) AS failed_jobs, SUM(total_jobs) AS total_jobs
+			FROM user_association WHERE config_id IN (SELECT config_id FROM owned) GROUP BY 1, 2)
+		SELECT COALESCE(a.config_id, s.config_id) AS config_id, COALESCE(a.user_id, s.user_id) AS user_id,
+			COALESCE(s.no_of_batches, 0) AS stored_batches, COALESCE(s.no_of_jobs, 0) AS stored_jobs,
+			COALESCE(s.succeeded_jobs, 0) AS stored_succeeded, COALESCE(s.failed_jobs, 0) AS stored_failed,
+			COALESCE(s.total_jobs, 0) AS stored_total,
+			COALESCE(a.batches, 0) AS actual_batches, COALESCE(a.single_jobs, 0) AS actual_single,
+			COALESCE(a.accepted_jobs, 0) AS actual_accepted, COALESCE(a.rejected_jobs, 0) AS actual_rejected,
+			COALESCE(a.total_jobs, 0) AS actual_total
+		FROM a FULL JOIN s ON a.config_id = s.config_id AND a.user_id = s.user_id
+		WHERE `+usageDiffers+`
+		ORDER BY 1, 2`, userID).Scan(&rows).Error
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to check config usage")
+		return
+	}
+
+	resp := models.UsageCheckResponse{Consistent: len(rows) == 0, Drift: make([]models.UsageDrift, len(rows))}
+	keys := make([]string, len(rows))
+	for i, row := range rows {
+		resp.Drift[i] = models.UsageDrift{
+			ConfigID: row.ConfigID,
+			UserID:   row.UserID,
+			Stored:   models.UsageCounters{NoOfBatches: row.StoredBatches, NoOfJobs: row.StoredJobs, SucceededJobs: row.StoredSucceeded, FailedJobs: row.StoredFailed, TotalJobs: row.StoredTotal},
+			Actual:   models.ConfigUsage{Batches: row.ActualBatches, SingleJobs: row.ActualSingle, AcceptedJobs: row.ActualAccepted, RejectedJobs: row.ActualRejected, TotalJobs: row.ActualTotal},
+		}
+		keys[i] = row.ConfigID.String()
+	}
+	names := configNames(keys)
+	for i := range resp.Drift {
+		resp.Drift[i].ConfigName = names[keys[i]]
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config usage checked", resp))
+}
+
+// usageCacheTTL is how long a config's usage is served from memory. Counting
+// scans the config's jobs, and GET /configs needs it for every config on a page.
+const usageCacheTTL = time.Minute
+
+var usageCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]usageCacheEntry
+}
+
+type usageCacheEntry struct {
+	usage     models.ConfigUsageResponse
+	expiresAt time.Time
+}
+
+// configUsage returns the usage of configs over all their users, counting the
+// jobs of those not cached within usageCacheTTL. Every config in ids gets an
+// entry, with zero usage if it has no jobs yet.
+func configUsage(ids []uuid.UUID) (map[uuid.UUID]*models.ConfigUsageResponse, error) {
+	usage := make(map[uuid.UUID]*models.ConfigUsageResponse, len(ids))
+	var missing []uuid.UUID
+	now := time.Now()
+
+	usageCache.mu.Lock()
+	for _, id := range ids {
+		if entry, ok := usageCache.entries[id]; ok && now.Before(entry.expiresAt) {
+			cached := entry.usage
+			usage[id] = &cached
+			continue
+		}
+		missing = append(missing, id)
+	}
+	usageCache.mu.Unlock()
+
+	counted, err := countConfigUsage(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	usageCache.mu.Lock()
+	defer usageCache.mu.Unlock()
+	if usageCache.entries == nil {
+		usageCache.entries = make(map[uuid.UUID]usageCacheEntry)
+	}
+	for id, entry := range usageCache.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(usageCache.entries, id)
+		}
+	}
+	for id, u := range counted {
+		usageCache.entries[id] = usageCacheEntry{usage: *u, expiresAt: now.Add(usageCacheTTL)}
+		usage[id] = u
+	}
+	return usage, nil
+}
+
+// countConfigUsage counts the jobs of configs over all their users
+func countConfigUsage(ids []uuid.UUID) (map[uuid.UUID]*models.ConfigUsageResponse, error) {
+	usage := make(map[uuid.UUID]*models.ConfigUsageResponse, len(ids))
+	for _, id := range ids {
+		usage[id] = &models.ConfigUsageResponse{ConfigID: id}
+	}
+	if len(ids) == 0 {
+		return usage, nil
+	}
+
+	var rows []struct {
+		ConfigID uuid.UUID
+		Users    int64
+		models.ConfigUsage
+	}
+	err := config.DB.Table("jobs").
+		Select("global_config_id AS config_id, COUNT(DISTINCT user_id) AS users, "+usageColumns).
+		Where("global_config_id IN ?", ids).
+		Group("global_config_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if u := usage[row.ConfigID]; u != nil {
+			u.Users = row.Users
+			u.ConfigUsage = row.ConfigUsage
+		}
+	}
+	return usage, nil
+}
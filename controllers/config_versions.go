@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Versions handles GET /configs/{id}/versions - list revisions of a config, newest first
+func (c *ConfigController) Versions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	// Count total
+	var total int64
+	config.DB.Model(&models.ConfigRevision{}).Where("config_id = ?", configID).Count(&total)
+
+	// Fetch revisions
+	var revisions []models.ConfigRevision
+	offset := (page - 1) * perPage
+	if err := config.DB.Where("config_id = ?", configID).
+		Order("version DESC").
+		Offset(offset).
+		Limit(perPage).
+		Find(&revisions).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch config versions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(revisions, page, perPage, total))
+}
+
+// GetVersion handles GET /configs/{id}/versions/{version} - get a single revision
+func (c *ConfigController) GetVersion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid version")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	revision, err := findRevision(config.DB, configID, version)
+	if err != nil {
+		respondError(w, r, models.ErrVersionNotFound, "Config version not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config version retrieved", revision))
+}
+
+// Diff handles GET /configs/{id}/diff?from=&to= - structured diff between two revisions.
+// to defaults to the current version and from to the version before it.
+func (c *ConfigController) Diff(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	to := cfg.CurrentVersion
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = strconv.Atoi(v); err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid to version", models.FieldError{Field: "to", Message: "must be an integer"})
+			return
+		}
+	}
+	from := to - 1
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = strconv.Atoi(v); err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid from version", models.FieldError{Field: "from", Message: "must be an integer"})
+			return
+		}
+	}
+
+	fromRevision, err := findRevision(config.DB, configID, from)
+	if err != nil {
+		respondError(w, r, models.ErrVersionNotFound, "Config version "+strconv.Itoa(from)+" not found")
+		return
+	}
+	toRevision, err := findRevision(config.DB, configID, to)
+	if err != nil {
+		respondError(w, r, models.ErrVersionNotFound, "Config version "+strconv.Itoa(to)+" not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config diff computed", models.ConfigDiffResponse{
+		ConfigID:    configID,
+		FromVersion: from,
+		ToVersion:   to,
+		FromName:    derefString(fromRevision.ConfigName),
+		ToName:      derefString(toRevision.ConfigName),
+		Changes:     models.DiffConfigs(fromRevision.Config, toRevision.Config),
+	}))
+}
+
+// Rollback handles POST /configs/{id}/rollback/{version} - restore an earlier revision.
+// The restored content is recorded as a new revision; history is never rewritten.
+func (c *ConfigController) Rollback(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid version")
+		return
+	}
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	if !checkIfMatch(w, r, cfg, false) {
+		tx.Rollback()
+		return
+	}
+	if !requireNoApproval(w, r, cfg) {
+		tx.Rollback()
+		return
+	}
+
+	revision, err := findRevision(tx, configID, version)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrVersionNotFound, "Config version not found")
+		return
+	}
+	if version == cfg.CurrentVersion {
+		tx.Rollback()
+		respondError(w, r, models.ErrValidationFailed, "Config is already at this version", models.FieldError{Field: "version", Message: "is the current version"})
+		return
+	}
+
+	cfg.ConfigName = revision.ConfigName
+	cfg.Config = revision.Config
+	if err := appendRevision(tx, cfg, userID, models.RevisionRolledBack, &version); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to roll back config")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+
+	setConfigETag(w, cfg)
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config rolled back", cfg.ToResponse()))
+}
+
+// lockUserConfig loads a user's config with a row lock for the rest of tx
+func lockUserConfig(tx *gorm.DB, userID, configID uuid.UUID) (*models.GlobalJobConfig, error) {
+	var cfg models.GlobalJobConfig
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("config_id = ? AND user_id = ?", configID, userID).
+		First(&cfg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// appendRevision snapshots cfg as its next revision and saves cfg pointing at it.
+// A change to the active config is queued for delivery to Janus.
+// cfg must be locked (or newly created) in tx.
+func appendRevision(tx *gorm.DB, cfg *models.GlobalJobConfig, authorID uuid.UUID, changeType string, sourceVersion *int) error {
+	revision := models.ConfigRevision{
+		RevisionID:    uuid.New(),
+		ConfigID:      cfg.ConfigID,
+		Version:       cfg.CurrentVersion + 1,
+		ConfigName:    cfg.ConfigName,
+		Config:        cfg.Config,
+		AuthorID:      authorID,
+		ChangeType:    changeType,
+		SourceVersion: sourceVersion,
+		CreatedAt:     time.Now(),
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return err
+	}
+
+	cfg.CurrentVersion = revision.Version
+	cfg.CurrentRevisionID = &revision.RevisionID
+	cfg.UpdatedAt = revision.CreatedAt
+	if cfg.Status == models.ConfigStatusActive {
+		markSyncPending(cfg)
+	}
+	return tx.Save(cfg).Error
+}
+
+func findRevision(db *gorm.DB, configID uuid.UUID, version int) (*models.ConfigRevision, error) {
+	var revision models.ConfigRevision
+	if err := db.Where("config_id = ? AND version = ?", configID, version).First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/models"
+
+	"gorm.io/gorm"
+)
+
+// Ways of counting the rows behind a cursor-paginated listing
+const (
+	countNone     = "none"
+	countExact    = "exact"
+	countEstimate = "estimate"
+)
+
+// pageCursor is the position of a row in (created_at, id) order. Rows without
+// created_at sort after all others, as Postgres sorts NULLs by default, and have
+// no CreatedAt. Backward cursors fetch the page before the row instead of the
+// page after it.
+type pageCursor struct {
+	CreatedAt *time.Time `json:"t,omitempty"`
+	ID        string     `json:"id"`
+	Backward  bool       `json:"b,omitempty"`
+}
+
+func (c pageCursor) encode() *string {
+	b, _ := json.Marshal(c)
+	s := base64.RawURLEncoding.EncodeToString(b)
+	return &s
+}
+
+func decodeCursor(s string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == "" || (c.CreatedAt != nil && c.CreatedAt.IsZero()) {
+		return nil, errors.New("incomplete cursor")
+	}
+	return &c, nil
+}
+
+// cursorPage is a parsed cursor pagination request
+type cursorPage struct {
+	limit  int
+	after  *pageCursor
+	desc   bool
+	count  string
+	column string
+}
+
+// wantsCursor reports whether a listing should use cursor instead of page pagination
+func wantsCursor(q url.Values) bool {
+	return q.Get("pagination") == "cursor" || q.Has("cursor")
+}
+
+// parseCursorPage reads cursor, per_page, order and count. idColumn is the
+// unique column that breaks created_at ties.
+func parseCursorPage(q url.Values, defaultLimit int, idColumn string) (*cursorPage, *models.FieldError) {
+	p := &cursorPage{limit: defaultLimit, desc: true, count: countNone, column: idColumn}
+
+	if perPage, _ := strconv.Atoi(q.Get("per_page")); perPage >= 1 && perPage <= 100 {
+		p.limit = perPage
+	}
+	if s := q.Get("cursor"); s != "" {
+		cursor, err := decodeCursor(s)
+		if err != nil {
+			return nil, &models.FieldError{Field: "cursor", Message: "is not a valid cursor"}
+		}
+		p.after = cursor
+	}
+	if sortParam := q.Get("sort"); sortParam != "" && sortParam != "created_at" {
+		return nil, &models.FieldError{Field: "sort", Message: "cursor pagination only supports created_at"}
+	}
+	switch q.Get("order") {
+	case "", "desc":
+	case "asc":
+		p.desc = false
+	default:
+		return nil, &models.FieldError{Field: "order", Message: "must be asc or desc"}
+	}
+	switch count := q.Get("count"); count {
+	case "":
+	case countNone, countExact, countEstimate:
+		p.count = count
+	default:
+		return nil, &models.FieldError{Field: "count", Message: "must be none, exact or estimate"}
+	}
+	return p, nil
+}
+
+// backward reports whether this request walks towards the start of the listing
+func (p *cursorPage) backward() bool {
+	return p.after != nil && p.after.Backward
+}
+
+// apply adds the keyset condition, order and limit to query. One extra row is
+// fetched to tell whether another page exists.
+func (p *cursorPage) apply(query *gorm.DB) *gorm.DB {
+	// Walking backwards reverses the scan; the page is flipped back in finish
+	desc := p.desc != p.backward()
+	// Rows without created_at come last, as in Postgres' default NULL order, so
+	// an index on created_at still serves the scan
+	dir, nulls := "ASC", "LAST"
+	if desc {
+		dir, nulls = "DESC", "FIRST"
+	}
+	switch {
+	case p.after == nil:
+	case p.after.CreatedAt == nil && desc:
+		query = query.Where("((created_at IS NULL AND "+p.column+" < ?) OR created_at IS NOT NULL)", p.after.ID)
+	case p.after.CreatedAt == nil:
+		query = query.Where("created_at IS NULL AND "+p.column+" > ?", p.after.ID)
+	case desc:
+		query = query.Where("(created_at, "+p.column+") < (?, ?)", *p.after.CreatedAt, p.after.ID)
+	default:
+		query = query.Where("((created_at, "+p.column+") > (?, ?) OR created_at IS NULL)", *p.after.CreatedAt, p.after.ID)
+	}
+	return query.Order("created_at " + dir + " NULLS " + nulls + ", " + p.column + " " + dir).Limit(p.limit + 1)
+}
+
+// finishCursorPage trims the extra row, restores display order and builds the
+// cursors on either side of the page. key returns the position of an item.
+func finishCursorPage[T any](p *cursorPage, items []T, key func(*T) pageCursor) ([]T, *string, *string) {
+	more := len(items) > p.limit
+	if more {
+		items = items[:p.limit]
+	}
+	if p.backward() {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if len(items) == 0 {
+		return items, nil, nil
+	}
+
+	// Moving forward there is a previous page whenever we started from a cursor;
+	// moving backward there is always a next page, the one we came from.
+	hasNext, hasPrev := more, p.after != nil
+	if p.backward() {
+		hasNext, hasPrev = true, more
+	}
+
+	var next, prev *string
+	if hasNext {
+		next = key(&items[len(items)-1]).encode()
+	}
+	if hasPrev {
+		first := key(&items[0])
+		first.Backward = true
+		prev = first.encode()
+	}
+	return items, next, prev
+}
+
+// countRows counts the rows matched by query as requested: exactly, from the
+// planner's estimate, or not at all
+func (p *cursorPage) countRows(query *gorm.DB, model interface{}) (*int64, bool) {
+	switch p.count {
+	case countExact:
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			return nil, false
+		}
+		return &total, false
+	case countEstimate:
+		// The dry-run SQL uses the driver's own placeholders, so run it on the
+		// underlying connection rather than through gorm's Raw
+		stmt := query.Session(&gorm.Session{DryRun: true}).Find(model).Statement
+		sqlDB, err := config.DB.DB()
+		if err != nil {
+			return nil, false
+		}
+		var plan string
+		if err := sqlDB.QueryRow("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Scan(&plan); err != nil {
+			return nil, false
+		}
+		var explained []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		if err := json.Unmarshal([]byte(plan), &explained); err != nil || len(explained) == 0 {
+			return nil, false
+		}
+		total := int64(explained[0].Plan.Rows)
+		return &total, true
+	default:
+		return nil, false
+	}
+}
+
+// jobCursor is the cursor position of a job
+func jobCursor(job *models.Job) pageCursor {
+	return pageCursor{ID: job.JobID, CreatedAt: job.CreatedAt}
+}
+
+// batchCursor is the cursor position of a batch
+func batchCursor(batch *models.Batch) pageCursor {
+	return pageCursor{ID: batch.BatchID, CreatedAt: batch.CreatedAt}
+}
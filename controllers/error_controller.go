@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ErrorController publishes the error catalog
+type ErrorController struct{}
+
+// NewErrorController creates a new ErrorController
+func NewErrorController() *ErrorController {
+	return &ErrorController{}
+}
+
+// List handles GET /errors - list every error code the API can return
+func (c *ErrorController) List(w http.ResponseWriter, r *http.Request) {
+	catalog := make([]models.ErrorInfo, 0, len(models.ErrorCatalog))
+	for _, info := range models.ErrorCatalog {
+		catalog = append(catalog, info)
+	}
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Code < catalog[j].Code })
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Error catalog retrieved", catalog))
+}
+
+// Get handles GET /errors/{code} - describe a single error code (the problem+json "type")
+func (c *ErrorController) Get(w http.ResponseWriter, r *http.Request) {
+	info, ok := models.ErrorCatalog[models.ErrorCode(chi.URLParam(r, "code"))]
+	if !ok {
+		respondError(w, r, models.ErrRouteNotFound, "Unknown error code")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Error code retrieved", info))
+}
@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ExportController handles streaming and asynchronous exports of jobs
+type ExportController struct {
+	queryFields []string
+}
+
+// NewExportController creates a new ExportController
+func NewExportController(cfg *config.AppConfig) *ExportController {
+	return &ExportController{queryFields: cfg.JobQueryFields}
+}
+
+// Jobs handles GET /jobs/export - stream the user's jobs as CSV, NDJSON or Parquet
+func (c *ExportController) Jobs(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	c.stream(w, r, userID, nil)
+}
+
+// Batch handles GET /batches/{id}/export - stream a batch's jobs as CSV, NDJSON or Parquet
+func (c *ExportController) Batch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	batchID := chi.URLParam(r, "id")
+	var batch models.Batch
+	if err := config.DB.Where("batch_id = ? AND user_id = ?", batchID, userID).First(&batch).Error; err != nil {
+		respondError(w, r, models.ErrBatchNotFound, "Batch not found")
+		return
+	}
+
+	c.stream(w, r, userID, &batchID)
+}
+
+// CreateJobs handles POST /jobs/export - queue an export of the user's jobs
+func (c *ExportController) CreateJobs(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	c.queue(w, r, userID, nil)
+}
+
+// CreateBatch handles POST /batches/{id}/export - queue an export of a batch's jobs
+func (c *ExportController) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	batchID := chi.URLParam(r, "id")
+	var batch models.Batch
+	if err := config.DB.Where("batch_id = ? AND user_id = ?", batchID, userID).First(&batch).Error; err != nil {
+		respondError(w, r, models.ErrBatchNotFound, "Batch not found")
+		return
+	}
+
+	c.queue(w, r, userID, &batchID)
+}
+
+// List handles GET /exports - list the user's asynchronous exports
+func (c *ExportController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := config.DB.Model(&models.JobExport{}).Where("user_id = ?", userID)
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var exports []models.JobExport
+	offset := (page - 1) * perPage
+	if err := query.Order("created_at DESC").Offset(offset).Limit(perPage).Find(&exports).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch exports")
+		return
+	}
+
+	responses := make([]models.JobExportResponse, len(exports))
+	for i := range exports {
+		responses[i] = exports[i].ToResponse()
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(responses, page, perPage, total))
+}
+
+// Get handles GET /exports/{id} - get the status of an export
+func (c *ExportController) Get(w http.ResponseWriter, r *http.Request) {
+	export, ok := c.loadExport(w, r)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Export retrieved", export.ToResponse()))
+}
+
+// Download handles GET /exports/{id}/download - download a completed export
+func (c *ExportController) Download(w http.ResponseWriter, r *http.Request) {
+	export, ok := c.loadExport(w, r)
+	if !ok {
+		return
+	}
+	if export.Status != models.ExportCompleted || export.FilePath == nil {
+		respondError(w, r, models.ErrExportNotReady, "Export is "+string(export.Status))
+		return
+	}
+
+	f, err := os.Open(*export.FilePath)
+	if err != nil {
+		respondError(w, r, models.ErrExportNotFound, "Export file is no longer available")
+		return
+	}
+	defer f.Close()
+
+	name := exportFilename(export.BatchID, export.Format, export.CreatedAt)
+	w.Header().Set("Content-Type", exportContentTypes[export.Format])
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	modified := export.CreatedAt
+	if export.CompletedAt != nil {
+		modified = *export.CompletedAt
+	}
+	http.ServeContent(w, r, name, modified, f)
+}
+
+// Delete handles DELETE /exports/{id} - cancel a queued or running export, or delete its file
+func (c *ExportController) Delete(w http.ResponseWriter, r *http.Request) {
+	export, ok := c.loadExport(w, r)
+	if !ok {
+		return
+	}
+
+	// A running export notices the missing row and removes its own file
+	if err := config.DB.Delete(&models.JobExport{}, "export_id = ?", export.ExportID).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to delete export")
+		return
+	}
+	removeExportFile(export)
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Export deleted", nil))
+}
+
+// stream writes the export straight to the response. Once the first byte is out
+// errors can no longer be reported, so a failed export aborts the connection
+// rather than ending the file cleanly.
+func (c *ExportController) stream(w http.ResponseWriter, r *http.Request, userID uuid.UUID, batchID *string) {
+	spec, fieldErr := parseExportSpec(r.URL.Query())
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+	query, fieldErr := exportQuery(userID, batchID, r.URL.Query(), c.queryFields)
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentTypes[spec.format])
+	w.Header().Set("Content-Disposition", `attachment; filename="`+exportFilename(batchID, spec.format, time.Now())+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	// Write errors surface on the next write, so a failed flush can be ignored
+	rc := http.NewResponseController(w)
+	flush := func() error {
+		rc.Flush()
+		return nil
+	}
+	n, err := exportJobs(query.WithContext(r.Context()), spec, w, flush)
+	if err != nil {
+		log.Printf("Export: streaming %s export for user %s failed after %d row(s): %v", spec.format, userID, n, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// queue validates the export request and records it for the ExportRunner
+func (c *ExportController) queue(w http.ResponseWriter, r *http.Request, userID uuid.UUID, batchID *string) {
+	spec, fieldErr := parseExportSpec(r.URL.Query())
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+	if _, fieldErr := exportQuery(userID, batchID, r.URL.Query(), c.queryFields); fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+
+	export := models.JobExport{
+		ExportID:  uuid.New(),
+		UserID:    userID,
+		BatchID:   batchID,
+		Format:    spec.format,
+		Query:     r.URL.Query().Encode(),
+		Status:    models.ExportPending,
+		CreatedAt: time.Now(),
+	}
+	if err := config.DB.Create(&export).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to queue export")
+		return
+	}
+	requestExport()
+
+	w.Header().Set("Location", "/exports/"+export.ExportID.String())
+	respondJSON(w, http.StatusAccepted, models.NewSuccessResponse("Export queued", export.ToResponse()))
+}
+
+func (c *ExportController) loadExport(w http.ResponseWriter, r *http.Request) (*models.JobExport, bool) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return nil, false
+	}
+
+	exportID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid export ID")
+		return nil, false
+	}
+
+	var export models.JobExport
+	if err := config.DB.Where("export_id = ? AND user_id = ?", exportID, userID).First(&export).Error; err != nil {
+		respondError(w, r, models.ErrExportNotFound, "Export not found")
+		return nil, false
+	}
+	return &export, true
+}
+
+// exportFilename names a download, such as jobs-20261018T101500Z.csv. Batch IDs
+// are reduced to characters that are safe in a Content-Disposition header.
+func exportFilename(batchID *string, format string, at time.Time) string {
+	prefix := "jobs"
+	if batchID != nil {
+		prefix = "batch-" + strings.Map(func(r rune) rune {
+			if r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				return r
+			}
+			return '_'
+		}, *batchID)
+	}
+	return prefix + "-" + at.UTC().Format("20060102T150405Z") + "." + format
+}
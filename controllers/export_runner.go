@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/models"
+
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// exportPollInterval is how often the runner looks for queued and expired exports
+	exportPollInterval = 5 * time.Second
+	// exportHeartbeat is how often a running export records that it is alive;
+	// exports silent for exportStaleAfter are assumed lost and run again
+	exportHeartbeat  = 30 * time.Second
+	exportStaleAfter = 5 * time.Minute
+)
+
+// errExportCancelled stops an export whose row was deleted while it ran
+var errExportCancelled = errors.New("export cancelled")
+
+// exportWake wakes the export runner as soon as an export is queued
+var exportWake = make(chan struct{}, 1)
+
+// requestExport asks the export runner to look for work now instead of at its next tick
+func requestExport() {
+	select {
+	case exportWake <- struct{}{}:
+	default:
+	}
+}
+
+// ExportRunner runs queued exports into files under a directory and deletes
+// them once they expire. Exports are claimed with SKIP LOCKED, so several
+// replicas can share the queue as long as they share the directory.
+type ExportRunner struct {
+	dir         string
+	retention   time.Duration
+	queryFields []string
+}
+
+// NewExportRunner creates an ExportRunner from the export settings in cfg
+func NewExportRunner(cfg *config.AppConfig) *ExportRunner {
+	retention := cfg.ExportRetention
+	if retention <= 0 {
+		retention = 24 * time.Hour
+	}
+	return &ExportRunner{dir: cfg.ExportDir, retention: retention, queryFields: cfg.JobQueryFields}
+}
+
+// Run processes exports until ctx is cancelled
+func (e *ExportRunner) Run(ctx context.Context) {
+	if err := os.MkdirAll(e.dir, 0o750); err != nil {
+		log.Printf("Export: cannot create export directory %s: %v", e.dir, err)
+		return
+	}
+
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			export, ok := e.claim(time.Now())
+			if !ok {
+				break
+			}
+			e.run(ctx, export)
+		}
+		e.expire(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-exportWake:
+		}
+	}
+}
+
+// claim marks the oldest queued export, or one whose runner went silent, as running
+func (e *ExportRunner) claim(now time.Time) (*models.JobExport, bool) {
+	tx := config.DB.Begin()
+
+	var export models.JobExport
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? OR (status = ? AND heartbeat_at < ?)", models.ExportPending, models.ExportRunning, now.Add(-exportStaleAfter)).
+		Order("created_at").
+		First(&export).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, false
+	}
+
+	export.Status = models.ExportRunning
+	export.StartedAt = &now
+	export.HeartbeatAt = &now
+	if err := tx.Model(&export).Updates(map[string]interface{}{
+		"status":       export.Status,
+		"started_at":   now,
+		"heartbeat_at": now,
+	}).Error; err != nil {
+		tx.Rollback()
+		log.Printf("Export: failed to claim export %s: %v", export.ExportID, err)
+		return nil, false
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, false
+	}
+	return &export, true
+}
+
+// run writes one export to a temporary file and moves it into place when complete
+func (e *ExportRunner) run(ctx context.Context, export *models.JobExport) {
+	q, _ := url.ParseQuery(export.Query)
+	spec, fieldErr := parseExportSpec(q)
+	if fieldErr != nil {
+		e.fail(export, errors.New(fieldErr.Field+" "+fieldErr.Message))
+		return
+	}
+	query, fieldErr := exportQuery(export.UserID, export.BatchID, q, e.queryFields)
+	if fieldErr != nil {
+		e.fail(export, errors.New(fieldErr.Field+" "+fieldErr.Message))
+		return
+	}
+
+	tmp, err := os.CreateTemp(e.dir, export.ExportID.String()+"-*.tmp")
+	if err != nil {
+		e.fail(export, err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	go e.heartbeat(runCtx, export, cancel)
+	n, err := exportJobs(query.WithContext(runCtx), spec, tmp, nil)
+	cancel(nil)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	// Cancelled exports are gone; exports cut short by shutdown are picked up
+	// again once their heartbeat goes stale
+	if errors.Is(context.Cause(runCtx), errExportCancelled) || ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		e.fail(export, err)
+		return
+	}
+
+	path := filepath.Join(e.dir, export.ExportID.String()+"."+spec.format)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		e.fail(export, err)
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		e.fail(export, err)
+		return
+	}
+
+	now := time.Now()
+	result := config.DB.Model(&models.JobExport{}).
+		Where("export_id = ? AND status = ?", export.ExportID, models.ExportRunning).
+		Updates(map[string]interface{}{
+			"status":       models.ExportCompleted,
+			"row_count":    n,
+			"size_bytes":   info.Size(),
+			"file_path":    path,
+			"completed_at": now,
+			"expires_at":   now.Add(e.retention),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		// Deleted while running, or the result could not be recorded
+		os.Remove(path)
+		if result.Error != nil {
+			log.Printf("Export: failed to complete export %s: %v", export.ExportID, result.Error)
+		}
+		return
+	}
+	log.Printf("Export: export %s completed with %d row(s)", export.ExportID, n)
+}
+
+// heartbeat records every exportHeartbeat that export is still running, however
+// long a single query or write takes, until ctx is done. It cancels the export
+// with errExportCancelled once its row is gone.
+func (e *ExportRunner) heartbeat(ctx context.Context, export *models.JobExport, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(exportHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			result := config.DB.Model(&models.JobExport{}).
+				Where("export_id = ? AND status = ?", export.ExportID, models.ExportRunning).
+				Update("heartbeat_at", now)
+			if result.Error != nil {
+				log.Printf("Export: failed to record heartbeat of export %s: %v", export.ExportID, result.Error)
+			} else if result.RowsAffected == 0 {
+				cancel(errExportCancelled)
+				return
+			}
+		}
+	}
+}
+
+// fail records why an export failed; failed exports expire like completed ones
+func (e *ExportRunner) fail(export *models.JobExport, cause error) {
+	log.Printf("Export: export %s failed: %v", export.ExportID, cause)
+	now := time.Now()
+	config.DB.Model(&models.JobExport{}).
+		Where("export_id = ? AND status = ?", export.ExportID, models.ExportRunning).
+		Updates(map[string]interface{}{
+			"status":       models.ExportFailed,
+			"error":        cause.Error(),
+			"completed_at": now,
+			"expires_at":   now.Add(e.retention),
+		})
+}
+
+// expire deletes exports past their expiry along with their files
+func (e *ExportRunner) expire(now time.Time) {
+	var expired []models.JobExport
+	if err := config.DB.Where("expires_at < ?", now).Find(&expired).Error; err != nil {
+		log.Printf("Export: failed to list expired exports: %v", err)
+		return
+	}
+	for i := range expired {
+		removeExportFile(&expired[i])
+		if err := config.DB.Delete(&models.JobExport{}, "export_id = ?", expired[i].ExportID).Error; err != nil {
+			log.Printf("Export: failed to delete export %s: %v", expired[i].ExportID, err)
+		}
+	}
+}
+
+// removeExportFile deletes an export's file, if it has one
+func removeExportFile(export *models.JobExport) {
+	if export.FilePath == nil {
+		return
+	}
+	if err := os.Remove(*export.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Export: failed to remove %s: %v", *export.FilePath, err)
+	}
+}
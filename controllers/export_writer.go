@@ -0,0 +1,358 @@
+package controllers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/models"
+	"janus-backend-api/parquet"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxPayloadColumns caps how many payload paths one export can flatten
+	maxPayloadColumns = 100
+	// exportFlushRows is how often buffered output is pushed to the client
+	exportFlushRows = 1000
+)
+
+// exportContentTypes maps export formats to their media types
+var exportContentTypes = map[string]string{
+	models.ExportCSV:     "text/csv; charset=utf-8",
+	models.ExportNDJSON:  "application/x-ndjson",
+	models.ExportParquet: "application/vnd.apache.parquet",
+}
+
+// jobExportColumns are the job fields every export starts with
+var jobExportColumns = []parquet.Column{
+	{Name: "job_id", Type: parquet.String},
+	{Name: "batch_id", Type: parquet.String},
+	{Name: "user_id", Type: parquet.String},
+	{Name: "job_status", Type: parquet.String},
+	{Name: "reason", Type: parquet.String},
+	{Name: "global_config_id", Type: parquet.String},
+	{Name: "config_revision_id", Type: parquet.String},
+	{Name: "config_version", Type: parquet.Int64},
+	{Name: "created_at", Type: parquet.Timestamp},
+}
+
+// exportSpec is what an export writes: its format, whether the raw payload is
+// included, and which payload paths are flattened into columns of their own
+type exportSpec struct {
+	format       string
+	payload      bool
+	payloadPaths [][]string
+	columns      []parquet.Column
+}
+
+// parseExportSpec reads format (csv, ndjson or parquet), payload (json or none)
+// and payload_columns (repeatable or comma-separated dot paths)
+func parseExportSpec(q url.Values) (*exportSpec, *models.FieldError) {
+	spec := &exportSpec{format: models.ExportCSV, payload: true}
+	if format := q.Get("format"); format != "" {
+		if _, ok := exportContentTypes[format]; !ok {
+			return nil, &models.FieldError{Field: "format", Message: "must be csv, ndjson or parquet"}
+		}
+		spec.format = format
+	}
+	switch q.Get("payload") {
+	case "", "json":
+	case "none":
+		spec.payload = false
+	default:
+		return nil, &models.FieldError{Field: "payload", Message: "must be json or none"}
+	}
+
+	spec.columns = append(spec.columns, jobExportColumns...)
+	if spec.payload {
+		spec.columns = append(spec.columns, parquet.Column{Name: "payload", Type: parquet.String})
+	}
+	paths := splitMulti(q["payload_columns"])
+	if len(paths) > maxPayloadColumns {
+		return nil, &models.FieldError{Field: "payload_columns", Message: "must list at most " + strconv.Itoa(maxPayloadColumns) + " paths"}
+	}
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		for _, key := range keys {
+			if key == "" {
+				return nil, &models.FieldError{Field: "payload_columns", Message: "invalid path " + path}
+			}
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		spec.payloadPaths = append(spec.payloadPaths, keys)
+		spec.columns = append(spec.columns, parquet.Column{Name: "payload." + path, Type: parquet.String})
+	}
+	return spec, nil
+}
+
+// row returns the values of job in column order. Payload values keep their JSON
+// types; each format decides how to write them.
+func (s *exportSpec) row(job *models.Job) []interface{} {
+	row := make([]interface{}, 0, len(s.columns))
+	row = append(row, job.JobID, optionalString(job.BatchID), job.UserID.String(), job.JobStatus, optionalString(job.Reason))
+	row = append(row, optionalUUID(job.GlobalConfigID), optionalUUID(job.ConfigRevisionID))
+	if job.ConfigVersion != nil {
+		row = append(row, int64(*job.ConfigVersion))
+	} else {
+		row = append(row, nil)
+	}
+	if job.CreatedAt != nil {
+		row = append(row, job.CreatedAt.UTC())
+	} else {
+		row = append(row, nil)
+	}
+	if s.payload {
+		if job.JobPayload != nil {
+			row = append(row, map[string]interface{}(job.JobPayload))
+		} else {
+			row = append(row, nil)
+		}
+	}
+	for _, path := range s.payloadPaths {
+		row = append(row, payloadValue(job.JobPayload, path))
+	}
+	return row
+}
+
+// payloadValue follows path through nested payload objects; missing keys are nil
+func payloadValue(payload map[string]interface{}, path []string) interface{} {
+	var value interface{} = payload
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if value, ok = object[key]; !ok {
+			return nil
+		}
+	}
+	return value
+}
+
+func optionalString(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func optionalUUID(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+// exportQuery selects the jobs an export covers, the user's or one batch's,
+// narrowed by the job list filters and payload search and in list order
+func exportQuery(userID uuid.UUID, batchID *string, q url.Values, queryFields []string) (*gorm.DB, *models.FieldError) {
+	query := config.DB.Model(&models.Job{}).Where("user_id = ?", userID)
+	if batchID != nil {
+		query = query.Where("batch_id = ?", *batchID)
+	}
+	query, fieldErr := applyJobFilters(query, q)
+	if fieldErr != nil {
+		return nil, fieldErr
+	}
+	if query, fieldErr = applyPayloadQuery(query, q, queryFields); fieldErr != nil {
+		return nil, fieldErr
+	}
+	order, fieldErr := jobOrder(q)
+	if fieldErr != nil {
+		return nil, fieldErr
+	}
+	return query.Order(order), nil
+}
+
+// exportJobs streams the jobs selected by query to out one row at a time and
+// returns how many were written. flush, if set, is called every exportFlushRows
+// rows after buffered output has been written to out.
+func exportJobs(query *gorm.DB, spec *exportSpec, out io.Writer, flush func() error) (int64, error) {
+	buf := bufio.NewWriterSize(out, 64<<10)
+	table, err := newTableWriter(spec.format, buf, spec.columns)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var n int64
+	for rows.Next() {
+		var job models.Job
+		if err := config.DB.ScanRows(rows, &job); err != nil {
+			return n, err
+		}
+		if err := table.Write(spec.row(&job)); err != nil {
+			return n, err
+		}
+		n++
+
+		if n%exportFlushRows == 0 {
+			if err := table.Flush(); err != nil {
+				return n, err
+			}
+			if err := buf.Flush(); err != nil {
+				return n, err
+			}
+			if flush != nil {
+				if err := flush(); err != nil {
+					return n, err
+				}
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	if err := table.Close(); err != nil {
+		return n, err
+	}
+	return n, buf.Flush()
+}
+
+// tableWriter writes rows in one export format
+type tableWriter interface {
+	Write(row []interface{}) error
+	// Flush pushes rows buffered by the format itself to the underlying writer
+	Flush() error
+	Close() error
+}
+
+func newTableWriter(format string, out io.Writer, columns []parquet.Column) (tableWriter, error) {
+	switch format {
+	case models.ExportNDJSON:
+		return &ndjsonTable{out: out, columns: columns}, nil
+	case models.ExportParquet:
+		w, err := parquet.NewWriter(out, columns)
+		if err != nil {
+			return nil, err
+		}
+		return &parquetTable{w: w, columns: columns}, nil
+	default:
+		t := &csvTable{w: csv.NewWriter(out)}
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = col.Name
+		}
+		if err := t.w.Write(header); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+}
+
+// csvTable writes a header row, then one record per row. Nulls are empty cells;
+// objects and arrays are written as JSON.
+type csvTable struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (t *csvTable) Write(row []interface{}) error {
+	t.record = t.record[:0]
+	for _, value := range row {
+		t.record = append(t.record, cellText(value))
+	}
+	return t.w.Write(t.record)
+}
+
+func (t *csvTable) Flush() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func (t *csvTable) Close() error {
+	return t.Flush()
+}
+
+// ndjsonTable writes one JSON object per line with keys in column order.
+// Payload values keep their JSON types.
+type ndjsonTable struct {
+	out     io.Writer
+	columns []parquet.Column
+	line    []byte
+}
+
+func (t *ndjsonTable) Write(row []interface{}) error {
+	t.line = append(t.line[:0], '{')
+	for i, value := range row {
+		if i > 0 {
+			t.line = append(t.line, ',')
+		}
+		key, _ := json.Marshal(t.columns[i].Name)
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		t.line = append(t.line, key...)
+		t.line = append(t.line, ':')
+		t.line = append(t.line, encoded...)
+	}
+	t.line = append(t.line, '}', '\n')
+	_, err := t.out.Write(t.line)
+	return err
+}
+
+func (t *ndjsonTable) Flush() error { return nil }
+
+func (t *ndjsonTable) Close() error { return nil }
+
+// parquetTable writes payload values as strings, JSON-encoding objects and arrays
+type parquetTable struct {
+	w       *parquet.Writer
+	columns []parquet.Column
+}
+
+func (t *parquetTable) Write(row []interface{}) error {
+	for i, value := range row {
+		if value == nil || t.columns[i].Type != parquet.String {
+			continue
+		}
+		if _, ok := value.(string); !ok {
+			row[i] = cellText(value)
+		}
+	}
+	return t.w.Write(row)
+}
+
+// Flush is a no-op: row groups are written as soon as they fill up
+func (t *parquetTable) Flush() error { return nil }
+
+func (t *parquetTable) Close() error {
+	return t.w.Close()
+}
+
+// cellText formats a value as text: strings as they are, times in RFC 3339 and
+// anything else as JSON
+func cellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
@@ -3,6 +3,11 @@ package controllers
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
+	"strings"
+
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
 )
 
 // respondJSON writes a JSON response
@@ -11,3 +16,28 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
+
+// respondError writes an error from the error catalog, negotiating the format with the client
+func respondError(w http.ResponseWriter, r *http.Request, code models.ErrorCode, message string, details ...models.FieldError) {
+	middleware.WriteError(w, r, code, message, details...)
+}
+
+// requiredFields returns a field error for each empty value, in field name order
+func requiredFields(fields map[string]string) []models.FieldError {
+	var details []models.FieldError
+	for field, value := range fields {
+		if value == "" {
+			details = append(details, models.FieldError{Field: field, Message: "is required"})
+		}
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].Field < details[j].Field })
+	return details
+}
+
+// likeEscaper escapes LIKE wildcards so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes s for use inside a LIKE/ILIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -9,21 +9,25 @@ import (
 	"janus-backend-api/models"
 
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 )
 
 // JobController handles job viewing endpoints
-type JobController struct{}
+type JobController struct {
+	queryFields []string
+}
 
-// NewJobController creates a new JobController
-func NewJobController() *JobController {
-	return &JobController{}
+// NewJobController creates a new JobController. queryFields are the payload
+// paths that can be searched with ?q=.
+func NewJobController(queryFields []string) *JobController {
+	return &JobController{queryFields: queryFields}
 }
 
-// List handles GET /jobs - list all user's jobs with pagination
+// List handles GET /jobs - list the user's jobs with filtering, sorting and pagination
 func (c *JobController) List(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -37,17 +41,24 @@ func (c *JobController) List(w http.ResponseWriter, r *http.Request) {
 		perPage = 20
 	}
 
-	// Optional filters
-	status := r.URL.Query().Get("status")
-	batchID := r.URL.Query().Get("batch_id")
-
 	// Build query
-	query := config.DB.Model(&models.Job{}).Where("user_id = ?", userID)
-	if status != "" {
-		query = query.Where("job_status = ?", status)
+	query, fieldErr := applyJobFilters(config.DB.Model(&models.Job{}).Where("user_id = ?", userID), r.URL.Query())
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+	if query, fieldErr = applyPayloadQuery(query, r.URL.Query(), c.queryFields); fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+	if wantsCursor(r.URL.Query()) {
+		listJobsByCursor(w, r, query, 20)
+		return
 	}
-	if batchID != "" {
-		query = query.Where("batch_id = ?", batchID)
+	order, fieldErr := jobOrder(r.URL.Query())
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
 	}
 
 	// Count total
@@ -57,8 +68,8 @@ func (c *JobController) List(w http.ResponseWriter, r *http.Request) {
 	// Fetch jobs
 	var jobs []models.Job
 	offset := (page - 1) * perPage
-	if err := query.Order("created_at DESC").Offset(offset).Limit(perPage).Find(&jobs).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch jobs"))
+	if err := query.Order(order).Offset(offset).Limit(perPage).Find(&jobs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch jobs")
 		return
 	}
 
@@ -75,7 +86,7 @@ func (c *JobController) List(w http.ResponseWriter, r *http.Request) {
 func (c *JobController) Get(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -83,18 +94,43 @@ func (c *JobController) Get(w http.ResponseWriter, r *http.Request) {
 
 	var job models.Job
 	if err := config.DB.Where("job_id = ? AND user_id = ?", jobID, userID).First(&job).Error; err != nil {
-		respondJSON(w, http.StatusNotFound, models.NewErrorResponse("Job not found"))
+		respondError(w, r, models.ErrJobNotFound, "Job not found")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Job retrieved", job.ToResponse()))
+	response := job.ToResponse()
+	if job.GlobalConfigID != nil {
+		response.ConfigName, response.ConfigDeleted = jobConfigName(&job)
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Job retrieved", response))
+}
+
+// jobConfigName names the config a job ran under, including configs in the trash.
+// Once a config is purged its name comes from the revision the job was stamped with.
+func jobConfigName(job *models.Job) (name string, deleted bool) {
+	var cfg models.GlobalJobConfig
+	err := config.DB.Unscoped().Select("config_id", "config_name", "deleted_at").
+		Where("config_id = ?", *job.GlobalConfigID).
+		First(&cfg).Error
+	if err == nil {
+		return derefString(cfg.ConfigName), cfg.DeletedAt.Valid
+	}
+
+	if job.ConfigRevisionID != nil {
+		var revision models.ConfigRevision
+		if err := config.DB.Where("revision_id = ?", *job.ConfigRevisionID).First(&revision).Error; err == nil {
+			return derefString(revision.ConfigName), true
+		}
+	}
+	return "", true
 }
 
 // Stats handles GET /jobs/stats - get job statistics
 func (c *JobController) Stats(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -120,3 +156,35 @@ func (c *JobController) Stats(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Stats retrieved", stats))
 }
+
+// listJobsByCursor responds with one cursor page of the jobs matched by query
+func listJobsByCursor(w http.ResponseWriter, r *http.Request, query *gorm.DB, defaultLimit int) {
+	p, fieldErr := parseCursorPage(r.URL.Query(), defaultLimit, "job_id")
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+	total, estimated := p.countRows(query.Session(&gorm.Session{}), &[]models.Job{})
+
+	var jobs []models.Job
+	if err := p.apply(query).Find(&jobs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch jobs")
+		return
+	}
+	jobs, next, prev := finishCursorPage(p, jobs, jobCursor)
+
+	responses := make([]models.JobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = job.ToResponse()
+	}
+
+	respondJSON(w, http.StatusOK, models.CursorResponse{
+		Success:         true,
+		Data:            responses,
+		PerPage:         p.limit,
+		NextCursor:      next,
+		PrevCursor:      prev,
+		TotalItems:      total,
+		TotalIsEstimate: estimated,
+	})
+}
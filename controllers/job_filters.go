@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/jobquery"
+	"janus-backend-api/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Expressions over job_payload. The job indexes in config/migrations.go are built
+// on the same expressions, so keep the two in step.
+const (
+	jobTenantExpr   = "(job_payload->>'tenant_id')"
+	jobPriorityExpr = `(CASE WHEN job_payload->>'priority' ~ '^-{0,1}[0-9]+(\.[0-9]+){0,1}$' THEN (job_payload->>'priority')::numeric END)`
+)
+
+// jobSortColumns maps the sort query param to columns and payload expressions
+var jobSortColumns = map[string]string{
+	"created_at": "created_at",
+	"status":     "job_status",
+	"reason":     "reason",
+	"config_id":  "global_config_id",
+	"tenant_id":  jobTenantExpr,
+	"priority":   jobPriorityExpr,
+}
+
+// applyJobFilters narrows a jobs query by the filter params shared by job listings:
+// status (repeatable or comma-separated), batch_id, config_id, reason, reason_contains,
+// tenant_id, priority, priority_min, priority_max, from and to. The first invalid
+// param is returned as a field error.
+func applyJobFilters(query *gorm.DB, q url.Values) (*gorm.DB, *models.FieldError) {
+	if statuses := splitMulti(q["status"]); len(statuses) > 0 {
+		for _, status := range statuses {
+			if !isJobStatus(status) {
+				return nil, &models.FieldError{Field: "status", Message: "must be one of " + strings.Join(jobStatuses(), ", ")}
+			}
+		}
+		query = query.Where("job_status IN ?", statuses)
+	}
+	if batchID := q.Get("batch_id"); batchID != "" {
+		query = query.Where("batch_id = ?", batchID)
+	}
+	if configID := q.Get("config_id"); configID != "" {
+		id, err := uuid.Parse(configID)
+		if err != nil {
+			return nil, &models.FieldError{Field: "config_id", Message: "must be a UUID"}
+		}
+		query = query.Where("global_config_id = ?", id)
+	}
+	if reason := q.Get("reason"); reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+	if contains := q.Get("reason_contains"); contains != "" {
+		query = query.Where("reason ILIKE ?", "%"+escapeLike(contains)+"%")
+	}
+	if tenants := splitMulti(q["tenant_id"]); len(tenants) > 0 {
+		query = query.Where(jobTenantExpr+" IN ?", tenants)
+	}
+	for _, bound := range []struct{ param, condition string }{
+		{"priority", jobPriorityExpr + " = ?"},
+		{"priority_min", jobPriorityExpr + " >= ?"},
+		{"priority_max", jobPriorityExpr + " <= ?"},
+	} {
+		value := q.Get(bound.param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, &models.FieldError{Field: bound.param, Message: "must be a number"}
+		}
+		query = query.Where(bound.condition, n)
+	}
+	for _, bound := range []struct{ param, condition string }{
+		{"from", "created_at >= ?"},
+		{"to", "created_at < ?"},
+	} {
+		value := q.Get(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, &models.FieldError{Field: bound.param, Message: "must be an RFC 3339 timestamp"}
+		}
+		query = query.Where(bound.condition, t)
+	}
+	return query, nil
+}
+
+// applyPayloadQuery narrows a jobs query by the payload search in the q param,
+// which may only reference the given payload paths
+func applyPayloadQuery(query *gorm.DB, q url.Values, fields []string) (*gorm.DB, *models.FieldError) {
+	src := q.Get("q")
+	if src == "" {
+		return query, nil
+	}
+	filter, err := jobquery.Compile(src, jobquery.Options{Fields: fields})
+	if err != nil {
+		return nil, &models.FieldError{Field: "q", Message: err.Error()}
+	}
+	return query.Where(filter.SQL, filter.Args...), nil
+}
+
+// jobOrder returns the ORDER BY for the sort and order params; job_id breaks ties
+func jobOrder(q url.Values) (string, *models.FieldError) {
+	column := "created_at"
+	if sortParam := q.Get("sort"); sortParam != "" {
+		var ok bool
+		if column, ok = jobSortColumns[sortParam]; !ok {
+			return "", &models.FieldError{Field: "sort", Message: "must be one of created_at, status, reason, config_id, tenant_id, priority"}
+		}
+	}
+	switch q.Get("order") {
+	case "", "desc":
+		return column + " DESC, job_id DESC", nil
+	case "asc":
+		return column + " ASC, job_id ASC", nil
+	default:
+		return "", &models.FieldError{Field: "order", Message: "must be asc or desc"}
+	}
+}
+
+// splitMulti accepts repeated params as well as comma-separated values
+func splitMulti(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// job_status is a Postgres enum owned by Janus. Its labels are read from the
+// catalog so unknown statuses are rejected up front instead of failing the query,
+// and re-read on a miss in case Janus added one.
+var jobStatusLabels struct {
+	mu     sync.Mutex
+	labels map[string]bool
+}
+
+func isJobStatus(status string) bool {
+	jobStatusLabels.mu.Lock()
+	defer jobStatusLabels.mu.Unlock()
+
+	if !jobStatusLabels.labels[status] {
+		loadJobStatusLabels()
+	}
+	return jobStatusLabels.labels[status]
+}
+
+func jobStatuses() []string {
+	jobStatusLabels.mu.Lock()
+	defer jobStatusLabels.mu.Unlock()
+
+	labels := make([]string, 0, len(jobStatusLabels.labels))
+	for label := range jobStatusLabels.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// loadJobStatusLabels must be called with jobStatusLabels.mu held
+func loadJobStatusLabels() {
+	var labels []string
+	err := config.DB.Raw(`SELECT e.enumlabel FROM pg_enum e JOIN pg_type t ON t.oid = e.enumtypid
+		WHERE t.typname = 'job_status' ORDER BY e.enumsortorder`).Scan(&labels).Error
+	if err != nil || len(labels) == 0 {
+		return
+	}
+	jobStatusLabels.labels = make(map[string]bool, len(labels))
+	for _, label := range labels {
+		jobStatusLabels.labels[label] = true
+	}
+}
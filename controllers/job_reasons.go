@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReasonLimit    = 10
+	maxReasonLimit        = 50
+	defaultBreakdownLimit = 5
+	maxBreakdownLimit     = 20
+	defaultReasonExamples = 3
+	maxReasonExamples     = 10
+	defaultPriorityBand   = 10
+)
+
+// jobReasonExpr normalizes a rejection reason so that reasons differing only in
+// IDs, quoted values, numbers, case or spacing group together. Rejected jobs
+// without a reason are grouped as "(none)".
+const jobReasonExpr = `COALESCE(NULLIF(lower(btrim(regexp_replace(regexp_replace(regexp_replace(regexp_replace(jobs.reason,
+	'[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}', '<id>', 'g'),
+	'''[^'']*''|"[^"]*"', '<str>', 'g'),
+	'\y[0-9]+(\.[0-9]+){0,1}\y', '<n>', 'g'),
+	'\s+', ' ', 'g'))), ''), '(none)')`
+
+// Reasons handles GET /jobs/stats/reasons - the most common normalized rejection
+// reasons in a time range, with their trend versus the previous period, example
+// jobs and breakdowns by tenant, priority band and config
+func (c *JobController) Reasons(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	q := r.URL.Query()
+	to := time.Now().UTC()
+	if s := q.Get("to"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid to", models.FieldError{Field: "to", Message: "must be an RFC 3339 timestamp"})
+			return
+		}
+		to = t.UTC()
+	}
+	from := to.Add(-7 * 24 * time.Hour)
+	if s := q.Get("from"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid from", models.FieldError{Field: "from", Message: "must be an RFC 3339 timestamp"})
+			return
+		}
+		from = t.UTC()
+	}
+	if !from.Before(to) {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid from", models.FieldError{Field: "from", Message: "must be before to"})
+		return
+	}
+	previousFrom := from.Add(-to.Sub(from))
+
+	limit, fieldErr := intParam(q.Get("limit"), "limit", defaultReasonLimit, 1, maxReasonLimit)
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid limit", *fieldErr)
+		return
+	}
+	breakdownLimit, fieldErr := intParam(q.Get("breakdown_limit"), "breakdown_limit", defaultBreakdownLimit, 1, maxBreakdownLimit)
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid breakdown_limit", *fieldErr)
+		return
+	}
+	examples, fieldErr := intParam(q.Get("examples"), "examples", defaultReasonExamples, 0, maxReasonExamples)
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid examples", *fieldErr)
+		return
+	}
+	band, fieldErr := intParam(q.Get("priority_band"), "priority_band", defaultPriorityBand, 1, 1000000)
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid priority_band", *fieldErr)
+		return
+	}
+
+	// Only rejected jobs count; from and to are applied per query
+	filters := r.URL.Query()
+	filters.Del("from")
+	filters.Del("to")
+	filters.Del("status")
+	rejected := func(start time.Time) (*gorm.DB, *models.FieldError) {
+		query := config.DB.Table("jobs").Where("jobs.user_id = ? AND jobs.job_status = 'rejected' AND jobs.created_at >= ? AND jobs.created_at < ?", userID, start, to)
+		query, fieldErr := applyJobFilters(query, filters)
+		if fieldErr != nil {
+			return nil, fieldErr
+		}
+		return applyPayloadQuery(query, filters, c.queryFields)
+	}
+	if _, fieldErr := rejected(previousFrom); fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+
+	// Count every reason in both periods, then keep the most common current ones
+	var counts []struct {
+		Reason   string
+		Sample   string
+		Count    int64
+		Previous int64
+	}
+	query, _ := rejected(previousFrom)
+	err := query.Select(jobReasonExpr+` AS reason, MIN(jobs.reason) AS sample,
+		COUNT(*) FILTER (WHERE jobs.created_at >= ?) AS count,
+		COUNT(*) FILTER (WHERE jobs.created_at < ?) AS previous`, from, from).
+		Group("1").
+		Scan(&counts).Error
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to compute rejection reasons")
+		return
+	}
+
+	resp := models.ReasonStatsResponse{From: from, To: to, PreviousFrom: previousFrom, Reasons: []models.ReasonStat{}}
+	for _, row := range counts {
+		resp.TotalRejected += row.Count
+		resp.PreviousRejected += row.Previous
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Reason < counts[j].Reason
+	})
+	// Reasons that only occurred in the previous period are not listed
+	for len(counts) > 0 && counts[len(counts)-1].Count == 0 {
+		counts = counts[:len(counts)-1]
+	}
+	if len(counts) > limit {
+		resp.ReasonsOmitted = int64(len(counts) - limit)
+		counts = counts[:limit]
+	}
+	if len(counts) == 0 {
+		respondJSON(w, http.StatusOK, models.NewSuccessResponse("Rejection reasons retrieved", resp))
+		return
+	}
+
+	index := make(map[string]int, len(counts))
+	keys := make([]string, len(counts))
+	for i, row := range counts {
+		stat := models.ReasonStat{
+			Reason:         row.Reason,
+			Sample:         row.Sample,
+			Count:          row.Count,
+			Share:          float64(row.Count) / float64(resp.TotalRejected),
+			Previous:       row.Previous,
+			Change:         row.Count - row.Previous,
+			ExampleJobIDs:  []string{},
+			ByTenant:       []models.ReasonBreakdown{},
+			ByPriorityBand: []models.ReasonBreakdown{},
+			ByConfig:       []models.ReasonBreakdown{},
+		}
+		switch {
+		case row.Previous == 0:
+			stat.Trend = models.TrendNew
+		case stat.Change > 0:
+			stat.Trend = models.TrendUp
+		case stat.Change < 0:
+			stat.Trend = models.TrendDown
+		default:
+			stat.Trend = models.TrendFlat
+		}
+		if row.Previous > 0 {
+			pct := float64(stat.Change) / float64(row.Previous) * 100
+			stat.ChangePct = &pct
+		}
+		resp.Reasons = append(resp.Reasons, stat)
+		index[row.Reason] = i
+		keys[i] = row.Reason
+	}
+
+	// Break the listed reasons down by tenant, priority band and config in one pass.
+	// GROUPING() tells the sets apart: 3 is tenant, 5 is band and 6 is config.
+	var breakdowns []struct {
+		Reason      string
+		Tenant      *string
+		Band        *float64
+		ConfigID    *string
+		GroupingSet int
+		Count       int64
+	}
+	query, _ = rejected(from)
+	sub := query.Where(jobReasonExpr+" IN ?", keys).
+		Select(jobReasonExpr+` AS reason, `+jobTenantExpr+` AS tenant,
+			floor(`+jobPriorityExpr+` / ?::numeric) * ?::numeric AS band,
+			jobs.global_config_id::text AS config_id`, band, band)
+	err = config.DB.Table("(?) AS j", sub).
+		Select("reason, tenant, band, config_id, GROUPING(tenant, band, config_id) AS grouping_set, COUNT(*) AS count").
+		Group("GROUPING SETS ((reason, tenant), (reason, band), (reason, config_id))").
+		Scan(&breakdowns).Error
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to compute rejection reasons")
+		return
+	}
+	sort.SliceStable(breakdowns, func(i, j int) bool { return breakdowns[i].Count > breakdowns[j].Count })
+	for _, row := range breakdowns {
+		i, ok := index[row.Reason]
+		if !ok {
+			continue
+		}
+		stat := &resp.Reasons[i]
+		var list *[]models.ReasonBreakdown
+		entry := models.ReasonBreakdown{Count: row.Count}
+		switch row.GroupingSet {
+		case 3:
+			list, entry.Key = &stat.ByTenant, row.Tenant
+		case 5:
+			list = &stat.ByPriorityBand
+			if row.Band != nil {
+				label := "[" + strconv.FormatFloat(*row.Band, 'f', -1, 64) + "," + strconv.FormatFloat(*row.Band+float64(band), 'f', -1, 64) + ")"
+				entry.Key = &label
+			}
+		case 6:
+			list, entry.Key = &stat.ByConfig, row.ConfigID
+		default:
+			continue
+		}
+		if len(*list) < breakdownLimit {
+			*list = append(*list, entry)
+		}
+	}
+	var configIDs []string
+	for _, stat := range resp.Reasons {
+		for _, b := range stat.ByConfig {
+			if b.Key != nil {
+				configIDs = append(configIDs, *b.Key)
+			}
+		}
+	}
+	names := configNames(configIDs)
+	for i := range resp.Reasons {
+		for j := range resp.Reasons[i].ByConfig {
+			if key := resp.Reasons[i].ByConfig[j].Key; key != nil {
+				resp.Reasons[i].ByConfig[j].Name = names[*key]
+			}
+		}
+	}
+
+	// The newest jobs of each listed reason
+	if examples > 0 {
+		var rows []struct {
+			Reason string
+			JobID  string
+		}
+		query, _ = rejected(from)
+		sub := query.Where(jobReasonExpr+" IN ?", keys).
+			Select(jobReasonExpr + ` AS reason, jobs.job_id,
+				ROW_NUMBER() OVER (PARTITION BY ` + jobReasonExpr + ` ORDER BY jobs.created_at DESC, jobs.job_id DESC) AS rn`)
+		err = config.DB.Table("(?) AS j", sub).
+			Select("reason, job_id").
+			Where("rn <= ?", examples).
+			Order("reason, rn").
+			Scan(&rows).Error
+		if err != nil {
+			respondError(w, r, models.ErrInternal, "Failed to compute rejection reasons")
+			return
+		}
+		for _, row := range rows {
+			if i, ok := index[row.Reason]; ok {
+				resp.Reasons[i].ExampleJobIDs = append(resp.Reasons[i].ExampleJobIDs, row.JobID)
+			}
+		}
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Rejection reasons retrieved", resp))
+}
+
+// intParam parses an optional integer query param within [min, max]
+func intParam(s, field string, def, min, max int) (int, *models.FieldError) {
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, &models.FieldError{Field: field, Message: "must be between " + strconv.Itoa(min) + " and " + strconv.Itoa(max)}
+	}
+	return n, nil
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxTimeseriesBuckets caps the points in each series
+	maxTimeseriesBuckets = 2000
+	defaultSeriesLimit   = 10
+	maxSeriesLimit       = 50
+)
+
+// jobDependencyJoin yields one row per dependency named in the payload, and a
+// single NULL row for jobs without dependencies
+const jobDependencyJoin = `LEFT JOIN LATERAL jsonb_object_keys(CASE WHEN jsonb_typeof(jobs.job_payload::jsonb->'dependencies') = 'object'
+	THEN jobs.job_payload::jsonb->'dependencies' END) AS dep(name) ON true`
+
+// timeseriesBuckets maps the bucket param to its width and default range
+var timeseriesBuckets = map[string]struct{ width, span time.Duration }{
+	models.BucketMinute: {time.Minute, time.Hour},
+	models.BucketHour:   {time.Hour, 24 * time.Hour},
+	models.BucketDay:    {24 * time.Hour, 30 * 24 * time.Hour},
+}
+
+// Timeseries handles GET /jobs/stats/timeseries - accepted and rejected counts per
+// time bucket, optionally split by tenant, config or dependency
+func (c *JobController) Timeseries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	q := r.URL.Query()
+	bucket := q.Get("bucket")
+	if bucket == "" {
+		bucket = models.BucketHour
+	}
+	size, ok := timeseriesBuckets[bucket]
+	if !ok {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid bucket", models.FieldError{Field: "bucket", Message: "must be minute, hour or day"})
+		return
+	}
+
+	to := time.Now().UTC()
+	if s := q.Get("to"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid to", models.FieldError{Field: "to", Message: "must be an RFC 3339 timestamp"})
+			return
+		}
+		to = t.UTC()
+	}
+	from := to.Add(-size.span)
+	if s := q.Get("from"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid from", models.FieldError{Field: "from", Message: "must be an RFC 3339 timestamp"})
+			return
+		}
+		from = t.UTC()
+	}
+	// Buckets are whole: the range starts at the bucket holding from
+	from = from.Truncate(size.width)
+	if !from.Before(to) {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid from", models.FieldError{Field: "from", Message: "must be before to"})
+		return
+	}
+	buckets := int((to.Sub(from) + size.width - 1) / size.width)
+	if buckets > maxTimeseriesBuckets {
+		respondError(w, r, models.ErrInvalidQueryParam, "Range too large", models.FieldError{Field: "bucket", Message: "range covers more than " + strconv.Itoa(maxTimeseriesBuckets) + " buckets; use a larger bucket or a shorter range"})
+		return
+	}
+
+	limit := defaultSeriesLimit
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxSeriesLimit {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid limit", models.FieldError{Field: "limit", Message: "must be between 1 and " + strconv.Itoa(maxSeriesLimit)})
+			return
+		}
+		limit = n
+	}
+
+	// The job filters and payload search narrow the counted jobs; from and to are applied above
+	filters := r.URL.Query()
+	filters.Del("from")
+	filters.Del("to")
+	query, fieldErr := applyJobFilters(config.DB.Table("jobs").Where("jobs.user_id = ? AND jobs.created_at >= ? AND jobs.created_at < ?", userID, from, to), filters)
+	if fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+	if query, fieldErr = applyPayloadQuery(query, filters, c.queryFields); fieldErr != nil {
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid "+fieldErr.Field, *fieldErr)
+		return
+	}
+
+	groupBy := q.Get("group_by")
+	groupExpr := "NULL::text"
+	switch groupBy {
+	case "":
+	case models.GroupByTenant:
+		groupExpr = jobTenantExpr
+	case models.GroupByConfig:
+		groupExpr = "global_config_id::text"
+	case models.GroupByDependency:
+		groupExpr = "dep.name"
+		query = query.Joins(jobDependencyJoin)
+	default:
+		respondError(w, r, models.ErrInvalidQueryParam, "Invalid group_by", models.FieldError{Field: "group_by", Message: "must be tenant, config or dependency"})
+		return
+	}
+
+	var rows []struct {
+		Bucket   time.Time
+		GroupKey *string
+		Accepted int64
+		Rejected int64
+	}
+	err := query.Select(`date_trunc(?, created_at) AS bucket, `+groupExpr+` AS group_key,
+		COUNT(*) FILTER (WHERE job_status = 'accepted') AS accepted,
+		COUNT(*) FILTER (WHERE job_status = 'rejected') AS rejected`, bucket).
+		Group("bucket, group_key").
+		Scan(&rows).Error
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to compute job time series")
+		return
+	}
+
+	// Keep the busiest groups; every kept series gets a point for every bucket
+	type groupTotal struct {
+		key   *string
+		total int64
+	}
+	totals := map[string]*groupTotal{}
+	for _, row := range rows {
+		key := seriesKey(row.GroupKey)
+		if totals[key] == nil {
+			totals[key] = &groupTotal{key: row.GroupKey}
+		}
+		totals[key].total += row.Accepted + row.Rejected
+	}
+	ranked := make([]*groupTotal, 0, len(totals))
+	for _, g := range totals {
+		ranked = append(ranked, g)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].total != ranked[j].total {
+			return ranked[i].total > ranked[j].total
+		}
+		return seriesKey(ranked[i].key) < seriesKey(ranked[j].key)
+	})
+	resp := models.TimeseriesResponse{From: from, To: to, Bucket: bucket, GroupBy: groupBy, Series: []models.TimeseriesSeries{}}
+	if groupBy == "" && len(ranked) == 0 {
+		ranked = append(ranked, &groupTotal{})
+	}
+	if len(ranked) > limit {
+		resp.GroupsOmitted = len(ranked) - limit
+		ranked = ranked[:limit]
+	}
+
+	index := make(map[string]int, len(ranked))
+	for i, g := range ranked {
+		points := make([]models.TimeseriesPoint, buckets)
+		for b := range points {
+			points[b].Bucket = from.Add(time.Duration(b) * size.width)
+		}
+		resp.Series = append(resp.Series, models.TimeseriesSeries{Group: g.key, Points: points})
+		index[seriesKey(g.key)] = i
+	}
+	for _, row := range rows {
+		i, ok := index[seriesKey(row.GroupKey)]
+		b := int(row.Bucket.UTC().Sub(from) / size.width)
+		if !ok || b < 0 || b >= buckets {
+			continue
+		}
+		series := &resp.Series[i]
+		series.Points[b].Accepted += row.Accepted
+		series.Points[b].Rejected += row.Rejected
+		series.Accepted += row.Accepted
+		series.Rejected += row.Rejected
+	}
+	if groupBy == models.GroupByConfig {
+		nameConfigSeries(resp.Series)
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Job time series retrieved", resp))
+}
+
+// seriesKey turns a group into a map key; the NULL group sorts first
+func seriesKey(group *string) string {
+	if group == nil {
+		return ""
+	}
+	return "=" + *group
+}
+
+// nameConfigSeries labels config series with config names
+func nameConfigSeries(series []models.TimeseriesSeries) {
+	var ids []string
+	for _, s := range series {
+		if s.Group != nil {
+			ids = append(ids, *s.Group)
+		}
+	}
+	names := configNames(ids)
+	for i := range series {
+		if series[i].Group != nil {
+			series[i].Name = names[*series[i].Group]
+		}
+	}
+}
+
+// configNames looks up the names of configs by ID, including deleted configs.
+// IDs that are not UUIDs are skipped.
+func configNames(keys []string) map[string]*string {
+	var ids []uuid.UUID
+	for _, key := range keys {
+		if id, err := uuid.Parse(key); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	names := make(map[string]*string, len(ids))
+	if len(ids) == 0 {
+		return names
+	}
+	var configs []models.GlobalJobConfig
+	config.DB.Unscoped().Select("config_id", "config_name").Where("config_id IN ?", ids).Find(&configs)
+	for _, cfg := range configs {
+		names[cfg.ConfigID.String()] = cfg.ConfigName
+	}
+	return names
+}
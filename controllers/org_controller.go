@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrgController handles orgs and their members. Configs shared with an org are
+// readable by its members, so only members can add or remove members.
+type OrgController struct{}
+
+// NewOrgController creates a new OrgController
+func NewOrgController() *OrgController {
+	return &OrgController{}
+}
+
+// List handles GET /orgs - list the orgs the user is a member of
+func (c *OrgController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	var orgs []models.Org
+	if err := config.DB.Where("org_id IN (?)", userOrgs(config.DB, userID)).Order("name").Find(&orgs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch orgs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Orgs retrieved", orgs))
+}
+
+// Create handles POST /orgs - create an org with the user as its first member
+func (c *OrgController) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	var req models.CreateOrgRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		respondError(w, r, models.ErrValidationFailed, "Org name is required", models.FieldError{Field: "name", Message: "is required"})
+		return
+	}
+
+	now := time.Now()
+	org := models.Org{
+		OrgID:     uuid.New(),
+		Name:      req.Name,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+
+	tx := config.DB.Begin()
+	var taken int64
+	tx.Model(&models.Org{}).Where("LOWER(name) = LOWER(?)", org.Name).Count(&taken)
+	if taken > 0 {
+		tx.Rollback()
+		respondError(w, r, models.ErrOrgNameTaken, "An org with this name already exists", models.FieldError{Field: "name", Message: "is already taken"})
+		return
+	}
+	if err := tx.Create(&org).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create org")
+		return
+	}
+	member := models.OrgMember{OrgID: org.OrgID, UserID: userID, AddedBy: userID, CreatedAt: now}
+	if err := tx.Create(&member).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create org")
+		return
+	}
+	tx.Commit()
+
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Org created", org))
+}
+
+// Members handles GET /orgs/{id}/members - list the members of an org (members only)
+func (c *OrgController) Members(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid org ID")
+		return
+	}
+	if !isOrgMember(config.DB, orgID, userID) {
+		respondError(w, r, models.ErrOrgNotFound, "Org not found")
+		return
+	}
+
+	var members []models.OrgMember
+	if err := config.DB.Where("org_id = ?", orgID).Order("created_at").Find(&members).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch members")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Members retrieved", members))
+}
+
+// AddMember handles POST /orgs/{id}/members - add a member by email (members only)
+func (c *OrgController) AddMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid org ID")
+		return
+	}
+
+	var req models.AddOrgMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if req.Email == "" {
+		respondError(w, r, models.ErrValidationFailed, "Email is required", models.FieldError{Field: "email", Message: "is required"})
+		return
+	}
+
+	if !isOrgMember(config.DB, orgID, userID) {
+		respondError(w, r, models.ErrOrgNotFound, "Org not found")
+		return
+	}
+
+	var user models.User
+	if err := config.DB.Where("LOWER(email) = LOWER(?)", strings.TrimSpace(req.Email)).First(&user).Error; err != nil {
+		respondError(w, r, models.ErrUserNotFound, "No user with this email")
+		return
+	}
+
+	member := models.OrgMember{
+		OrgID:     orgID,
+		UserID:    user.UserID,
+		AddedBy:   userID,
+		CreatedAt: time.Now(),
+	}
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&member).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to add member")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Member added", member))
+}
+
+// RemoveMember handles DELETE /orgs/{id}/members/{userId} - remove a member or leave an org (members only)
+func (c *OrgController) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid org ID")
+		return
+	}
+	memberID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid user ID")
+		return
+	}
+
+	if !isOrgMember(config.DB, orgID, userID) {
+		respondError(w, r, models.ErrOrgNotFound, "Org not found")
+		return
+	}
+
+	result := config.DB.Where("org_id = ? AND user_id = ?", orgID, memberID).Delete(&models.OrgMember{})
+	if result.Error != nil {
+		respondError(w, r, models.ErrInternal, "Failed to remove member")
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondError(w, r, models.ErrUserNotFound, "User is not a member of this org")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Member removed", nil))
+}
+
+// userOrgs selects the IDs of the orgs userID is a member of
+func userOrgs(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&models.OrgMember{}).Select("org_id").Where("user_id = ?", userID)
+}
+
+// isOrgMember reports whether userID is a member of orgID
+func isOrgMember(db *gorm.DB, orgID, userID uuid.UUID) bool {
+	var n int64
+	db.Model(&models.OrgMember{}).Where("org_id = ? AND user_id = ?", orgID, userID).Count(&n)
+	return n > 0
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ScheduleController handles scheduled activations and activation history
+type ScheduleController struct{}
+
+// NewScheduleController creates a new ScheduleController
+func NewScheduleController() *ScheduleController {
+	return &ScheduleController{}
+}
+
+// List handles GET /configs/schedules - list the user's schedules
+func (c *ScheduleController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", userID)
+	if configID := r.URL.Query().Get("config_id"); configID != "" {
+		id, err := uuid.Parse(configID)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid config_id", models.FieldError{Field: "config_id", Message: "must be a UUID"})
+			return
+		}
+		query = query.Where("config_id = ?", id)
+	}
+
+	var schedules []models.ConfigSchedule
+	if err := query.Order("created_at DESC").Find(&schedules).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch schedules")
+		return
+	}
+
+	responses := make([]models.ScheduleResponse, len(schedules))
+	for i := range schedules {
+		responses[i] = schedules[i].ToResponse()
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Schedules retrieved", responses))
+}
+
+// Create handles POST /configs/schedules - schedule a one-off activation or a recurring window
+func (c *ScheduleController) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	var req models.CreateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if errs := req.Validate(); len(errs) > 0 {
+		respondError(w, r, models.ErrValidationFailed, "Invalid schedule", errs...)
+		return
+	}
+
+	// Both configs must belong to the user
+	ids := []uuid.UUID{req.ConfigID}
+	if req.FallbackConfigID != nil {
+		ids = append(ids, *req.FallbackConfigID)
+	}
+	var configs []models.GlobalJobConfig
+	if err := config.DB.Where("user_id = ? AND config_id IN ?", userID, ids).Find(&configs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch config")
+		return
+	}
+	if len(configs) != len(ids) {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	for i := range configs {
+		if !requireNoApproval(w, r, &configs[i]) {
+			return
+		}
+	}
+
+	schedule := models.ConfigSchedule{
+		ScheduleID: uuid.New(),
+		UserID:     userID,
+		ConfigID:   req.ConfigID,
+		Kind:       req.Kind,
+		Enabled:    true,
+	}
+	if req.Kind == models.ScheduleOnce {
+		at := req.ActivateAt.UTC()
+		schedule.ActivateAt = &at
+	} else {
+		schedule.Days = normalizeDays(req.Days)
+		schedule.StartTime = req.StartTime
+		schedule.EndTime = req.EndTime
+		schedule.Timezone = req.Timezone
+		schedule.FallbackConfigID = req.FallbackConfigID
+	}
+
+	if err := config.DB.Create(&schedule).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to create schedule")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Schedule created", schedule.ToResponse()))
+}
+
+// Get handles GET /configs/schedules/{scheduleId} - get a schedule
+func (c *ScheduleController) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	scheduleID, err := uuid.Parse(chi.URLParam(r, "scheduleId"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid schedule ID")
+		return
+	}
+
+	var schedule models.ConfigSchedule
+	if err := config.DB.Where("schedule_id = ? AND user_id = ?", scheduleID, userID).First(&schedule).Error; err != nil {
+		respondError(w, r, models.ErrScheduleNotFound, "Schedule not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Schedule retrieved", schedule.ToResponse()))
+}
+
+// Delete handles DELETE /configs/schedules/{scheduleId} - cancel a schedule
+func (c *ScheduleController) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	scheduleID, err := uuid.Parse(chi.URLParam(r, "scheduleId"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid schedule ID")
+		return
+	}
+
+	result := config.DB.Where("schedule_id = ? AND user_id = ?", scheduleID, userID).Delete(&models.ConfigSchedule{})
+	if result.Error != nil {
+		respondError(w, r, models.ErrInternal, "Failed to delete schedule")
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondError(w, r, models.ErrScheduleNotFound, "Schedule not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Schedule deleted", nil))
+}
+
+// Activations handles GET /configs/activations - activation history, newest first
+func (c *ScheduleController) Activations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := config.DB.Model(&models.ConfigActivation{}).Where("user_id = ?", userID)
+	if configID := r.URL.Query().Get("config_id"); configID != "" {
+		id, err := uuid.Parse(configID)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid config_id", models.FieldError{Field: "config_id", Message: "must be a UUID"})
+			return
+		}
+		query = query.Where("config_id = ?", id)
+	}
+	if trigger := r.URL.Query().Get("trigger"); trigger != "" {
+		query = query.Where("trigger = ?", trigger)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var activations []models.ConfigActivation
+	offset := (page - 1) * perPage
+	if err := query.Order("created_at DESC").Offset(offset).Limit(perPage).Find(&activations).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch activation history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(activations, page, perPage, total))
+}
+
+// normalizeDays lowercases and de-duplicates day names, keeping week order
+func normalizeDays(days []string) string {
+	seen := make(map[string]bool, len(days))
+	for _, d := range days {
+		seen[strings.ToLower(d)] = true
+	}
+	var ordered []string
+	for _, d := range []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"} {
+		if seen[d] {
+			ordered = append(ordered, d)
+		}
+	}
+	return strings.Join(ordered, ",")
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errors that disable a schedule instead of being retried
+var (
+	errScheduleTargetGone    = errors.New("config no longer exists")
+	errScheduleNeedsApproval = errors.New("config requires approval")
+)
+
+// ScheduleEvaluator applies due one-off activations and opens and closes
+// activation windows, using the same transactional switch as manual activation.
+// It also expires change requests past their deadline.
+type ScheduleEvaluator struct {
+	interval time.Duration
+}
+
+// NewScheduleEvaluator creates a ScheduleEvaluator that runs every interval
+func NewScheduleEvaluator(interval time.Duration) *ScheduleEvaluator {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &ScheduleEvaluator{interval: interval}
+}
+
+// Run evaluates schedules until ctx is cancelled
+func (e *ScheduleEvaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.evaluate(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *ScheduleEvaluator) evaluate(now time.Time) {
+	var ids []uuid.UUID
+	if err := config.DB.Model(&models.ConfigSchedule{}).Where("enabled").Order("created_at").Pluck("schedule_id", &ids).Error; err != nil {
+		log.Printf("Schedules: failed to list schedules: %v", err)
+		return
+	}
+	for _, id := range ids {
+		e.evaluateSchedule(id, now)
+	}
+	expireChangeRequests(now)
+}
+
+// evaluateSchedule handles one schedule in its own transaction. Rows locked by
+// another replica are skipped, so each transition happens once.
+func (e *ScheduleEvaluator) evaluateSchedule(scheduleID uuid.UUID, now time.Time) {
+	tx := config.DB.Begin()
+
+	var s models.ConfigSchedule
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("schedule_id = ? AND enabled", scheduleID).
+		First(&s).Error
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	var changed bool
+	switch s.Kind {
+	case models.ScheduleOnce:
+		changed, err = e.fireOnce(tx, &s, now)
+	case models.ScheduleWindow:
+		changed, err = e.applyWindow(tx, &s, now)
+	}
+	if err != nil {
+		tx.Rollback()
+		log.Printf("Schedules: schedule %s failed: %v", s.ScheduleID, err)
+		updates := map[string]interface{}{"last_error": err.Error()}
+		if errors.Is(err, errScheduleTargetGone) || errors.Is(err, errScheduleNeedsApproval) {
+			updates["enabled"] = false
+		}
+		config.DB.Model(&models.ConfigSchedule{}).Where("schedule_id = ?", s.ScheduleID).Updates(updates)
+		return
+	}
+	if !changed {
+		tx.Rollback()
+		return
+	}
+
+	s.LastError = nil
+	if err := tx.Save(&s).Error; err != nil {
+		tx.Rollback()
+		log.Printf("Schedules: failed to save schedule %s: %v", s.ScheduleID, err)
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+}
+
+// lockScheduledConfig locks a config a schedule is about to switch to
+func lockScheduledConfig(tx *gorm.DB, userID, configID uuid.UUID) (*models.GlobalJobConfig, error) {
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errScheduleTargetGone
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RequiresApproval {
+		return nil, errScheduleNeedsApproval
+	}
+	return cfg, nil
+}
+
+// fireOnce activates the config once activate_at has passed, then disables the schedule
+func (e *ScheduleEvaluator) fireOnce(tx *gorm.DB, s *models.ConfigSchedule, now time.Time) (bool, error) {
+	if s.ActivateAt == nil || now.Before(*s.ActivateAt) {
+		return false, nil
+	}
+
+	cfg, err := lockScheduledConfig(tx, s.UserID, s.ConfigID)
+	if err != nil {
+		return false, err
+	}
+	if err := activateConfig(tx, cfg, activation{Trigger: models.TriggerSchedule, ScheduleID: &s.ScheduleID}); err != nil {
+		return false, err
+	}
+
+	s.FiredAt = &now
+	s.Enabled = false
+	return true, nil
+}
+
+// applyWindow acts only when the window opens or closes, so a manual activation
+// made while the window is open is left alone until the next transition
+func (e *ScheduleEvaluator) applyWindow(tx *gorm.DB, s *models.ConfigSchedule, now time.Time) (bool, error) {
+	open, err := s.WindowOpen(now)
+	if err != nil {
+		return false, err
+	}
+	if open == s.InWindow {
+		return false, nil
+	}
+
+	by := activation{Trigger: models.TriggerWindow, ScheduleID: &s.ScheduleID}
+	cfg, err := lockScheduledConfig(tx, s.UserID, s.ConfigID)
+	if err != nil {
+		return false, err
+	}
+
+	if open {
+		if cfg.Status != models.ConfigStatusActive {
+			if err := activateConfig(tx, cfg, by); err != nil {
+				return false, err
+			}
+		}
+	} else if cfg.Status == models.ConfigStatusActive {
+		if s.FallbackConfigID != nil {
+			fallback, err := lockScheduledConfig(tx, s.UserID, *s.FallbackConfigID)
+			if err != nil {
+				return false, err
+			}
+			if err := activateConfig(tx, fallback, by); err != nil {
+				return false, err
+			}
+		} else if err := deactivateConfig(tx, cfg, by); err != nil {
+			return false, err
+		}
+	}
+
+	s.InWindow = open
+	return true, nil
+}
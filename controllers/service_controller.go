@@ -0,0 +1,382 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// serviceResumeInterval is how often pauses past their resume time are ended
+	serviceResumeInterval = 10 * time.Second
+	maxPauseReasonLength  = 500
+)
+
+// errServicePaused is recorded on submissions refused while paused
+var errServicePaused = errors.New("submissions paused")
+
+// ServiceController handles pausing and resuming a user's submissions
+type ServiceController struct{}
+
+// NewServiceController creates a new ServiceController
+func NewServiceController() *ServiceController {
+	return &ServiceController{}
+}
+
+// Status handles GET /service/status - get whether submissions are paused
+func (c *ServiceController) Status(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	status, pause, err := serviceState(config.DB, userID, time.Now())
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch service status")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Service status retrieved", models.ServiceStatusResponse{Status: status, Pause: pause}))
+}
+
+// Pause handles POST /service/pause - stop accepting submissions, optionally until a given time
+func (c *ServiceController) Pause(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	var req models.PauseServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		respondError(w, r, models.ErrValidationFailed, "Reason is required", models.FieldError{Field: "reason", Message: "is required"})
+		return
+	}
+	if len(req.Reason) > maxPauseReasonLength {
+		respondError(w, r, models.ErrValidationFailed, "Reason is too long", models.FieldError{Field: "reason", Message: "must be at most " + strconv.Itoa(maxPauseReasonLength) + " characters"})
+		return
+	}
+
+	now := time.Now()
+	resumeAt := req.ResumeAt
+	if req.ResumeAfter != "" {
+		if resumeAt != nil {
+			respondError(w, r, models.ErrValidationFailed, "Set resume_at or resume_after, not both", models.FieldError{Field: "resume_after", Message: "cannot be combined with resume_at"})
+			return
+		}
+		d, err := time.ParseDuration(req.ResumeAfter)
+		if err != nil || d <= 0 {
+			respondError(w, r, models.ErrValidationFailed, "Invalid resume_after", models.FieldError{Field: "resume_after", Message: "must be a positive duration such as 30m or 2h"})
+			return
+		}
+		at := now.Add(d)
+		resumeAt = &at
+	}
+	if resumeAt != nil && !resumeAt.After(now) {
+		respondError(w, r, models.ErrValidationFailed, "Invalid resume_at", models.FieldError{Field: "resume_at", Message: "must be in the future"})
+		return
+	}
+
+	tx := config.DB.Begin()
+	status, err := lockServiceStatus(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to pause submissions")
+		return
+	}
+	current, _, err := serviceState(tx, userID, now)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to pause submissions")
+		return
+	}
+	if current == models.ServicePaused {
+		tx.Rollback()
+		respondError(w, r, models.ErrAlreadyPaused, "Submissions are already paused")
+		return
+	}
+	// A pause whose resume time passed before the resumer got to it ends now
+	pause, err := openPause(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to pause submissions")
+		return
+	}
+	if pause != nil {
+		if err := endPause(tx, pause, now, nil); err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to pause submissions")
+			return
+		}
+	}
+
+	pause = &models.ServicePause{
+		PauseID:  uuid.New(),
+		UserID:   userID,
+		PausedBy: userID,
+		Reason:   req.Reason,
+		PausedAt: now,
+		ResumeAt: resumeAt,
+	}
+	if err := tx.Create(pause).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to pause submissions")
+		return
+	}
+	if err := tx.Model(status).Update("status", models.ServicePaused).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to pause submissions")
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to pause submissions")
+		return
+	}
+	log.Printf("Service: submissions paused for user %s: %s", userID, req.Reason)
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Submissions paused", models.ServiceStatusResponse{Status: models.ServicePaused, Pause: pause}))
+}
+
+// Resume handles POST /service/resume - accept submissions again
+func (c *ServiceController) Resume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	now := time.Now()
+	tx := config.DB.Begin()
+	status, err := lockServiceStatus(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to resume submissions")
+		return
+	}
+	if status.Status != models.ServicePaused {
+		tx.Rollback()
+		respondError(w, r, models.ErrNotPaused, "Submissions are not paused")
+		return
+	}
+
+	pause, err := openPause(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to resume submissions")
+		return
+	}
+	if pause != nil {
+		if err := endPause(tx, pause, now, &userID); err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to resume submissions")
+			return
+		}
+	}
+	if err := tx.Model(status).Update("status", models.ServiceRunning).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to resume submissions")
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to resume submissions")
+		return
+	}
+	log.Printf("Service: submissions resumed for user %s", userID)
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Submissions resumed", models.ServiceStatusResponse{Status: models.ServiceRunning, Pause: pause}))
+}
+
+// Pauses handles GET /service/pauses - list past and current pauses, newest first
+func (c *ServiceController) Pauses(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := config.DB.Model(&models.ServicePause{}).Where("user_id = ?", userID)
+
+	var total int64
+	query.Count(&total)
+
+	var pauses []models.ServicePause
+	offset := (page - 1) * perPage
+	if err := query.Order("paused_at DESC").Offset(offset).Limit(perPage).Find(&pauses).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch pauses")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(pauses, page, perPage, total))
+}
+
+// serviceState reports whether a user's submissions are paused at now, with the
+// open pause while paused. A pause past its resume time no longer counts, even
+// before the ServiceResumer has ended it.
+func serviceState(db *gorm.DB, userID uuid.UUID, now time.Time) (string, *models.ServicePause, error) {
+	var status models.ServiceStatus
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&status).Error; err != nil {
+		return "", nil, err
+	}
+	if status.Status != models.ServicePaused {
+		return models.ServiceRunning, nil, nil
+	}
+	pause, err := openPause(db, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if pause != nil && pause.ResumeAt != nil && !pause.ResumeAt.After(now) {
+		return models.ServiceRunning, nil, nil
+	}
+	return models.ServicePaused, pause, nil
+}
+
+// openPause returns the user's pause that has not ended yet, or nil
+func openPause(db *gorm.DB, userID uuid.UUID) (*models.ServicePause, error) {
+	var pauses []models.ServicePause
+	if err := db.Where("user_id = ? AND resumed_at IS NULL", userID).Limit(1).Find(&pauses).Error; err != nil {
+		return nil, err
+	}
+	if len(pauses) == 0 {
+		return nil, nil
+	}
+	return &pauses[0], nil
+}
+
+// lockServiceStatus locks the user's service_status row, creating it as running
+// if it does not exist yet
+func lockServiceStatus(tx *gorm.DB, userID uuid.UUID) (*models.ServiceStatus, error) {
+	status := models.ServiceStatus{UserID: userID, Status: models.ServiceRunning}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&status).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&status).Error; err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// endPause closes a pause; without resumedBy it counts as resumed automatically
+func endPause(tx *gorm.DB, pause *models.ServicePause, at time.Time, resumedBy *uuid.UUID) error {
+	pause.ResumedAt = &at
+	pause.ResumedBy = resumedBy
+	pause.AutoResumed = resumedBy == nil
+	return tx.Model(pause).Updates(map[string]interface{}{
+		"resumed_at":   at,
+		"resumed_by":   resumedBy,
+		"auto_resumed": pause.AutoResumed,
+	}).Error
+}
+
+// respondIfPaused refuses a submission with 503 SERVICE_PAUSED while the user's
+// submissions are paused, and reports whether it did. If the status cannot be
+// read the submission goes ahead.
+func respondIfPaused(w http.ResponseWriter, r *http.Request, userID uuid.UUID) bool {
+	now := time.Now()
+	status, pause, err := serviceState(config.DB, userID, now)
+	if err != nil {
+		log.Printf("Service: failed to read status for user %s: %v", userID, err)
+		return false
+	}
+	if status != models.ServicePaused {
+		return false
+	}
+
+	message := "Submissions are paused"
+	if pause != nil {
+		message += ": " + pause.Reason
+		if pause.ResumeAt != nil {
+			seconds := int64(pause.ResumeAt.Sub(now)/time.Second) + 1
+			w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+			message += " (resumes at " + pause.ResumeAt.UTC().Format(time.RFC3339) + ")"
+		}
+	}
+	respondError(w, r, models.ErrServicePaused, message)
+	return true
+}
+
+// ServiceResumer ends pauses whose resume time has passed
+type ServiceResumer struct{}
+
+// NewServiceResumer creates a new ServiceResumer
+func NewServiceResumer() *ServiceResumer {
+	return &ServiceResumer{}
+}
+
+// Run resumes due pauses until ctx is cancelled
+func (s *ServiceResumer) Run(ctx context.Context) {
+	ticker := time.NewTicker(serviceResumeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.resumeDue(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ServiceResumer) resumeDue(now time.Time) {
+	tx := config.DB.Begin()
+
+	var due []models.ServicePause
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("resumed_at IS NULL AND resume_at <= ?", now).
+		Order("resume_at").
+		Limit(100).
+		Find(&due).Error
+	if err != nil || len(due) == 0 {
+		tx.Rollback()
+		return
+	}
+
+	for i := range due {
+		if err := endPause(tx, &due[i], now, nil); err != nil {
+			tx.Rollback()
+			log.Printf("Service: failed to end pause %s: %v", due[i].PauseID, err)
+			return
+		}
+		if err := tx.Model(&models.ServiceStatus{}).
+			Where("user_id = ? AND status = ?", due[i].UserID, models.ServicePaused).
+			Update("status", models.ServiceRunning).Error; err != nil {
+			tx.Rollback()
+			log.Printf("Service: failed to resume user %s: %v", due[i].UserID, err)
+			return
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		log.Printf("Service: failed to commit resumes: %v", err)
+		return
+	}
+	log.Printf("Service: resumed submissions for %d user(s) automatically", len(due))
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ShadowController handles viewing of shadow traffic results
+type ShadowController struct{}
+
+// NewShadowController creates a new ShadowController
+func NewShadowController() *ShadowController {
+	return &ShadowController{}
+}
+
+// List handles GET /shadow/diffs - list admission differences between primary and shadow
+func (c *ShadowController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	// Optional filters
+	endpoint := r.URL.Query().Get("endpoint")
+	jobID := r.URL.Query().Get("job_id")
+
+	query := config.DB.Model(&models.ShadowDiff{}).Where("user_id = ?", userID)
+	if endpoint != "" {
+		query = query.Where("endpoint = ?", endpoint)
+	}
+	if jobID != "" {
+		query = query.Where("job_id = ?", jobID)
+	}
+
+	// Count total
+	var total int64
+	query.Count(&total)
+
+	// Fetch diffs
+	var diffs []models.ShadowDiff
+	offset := (page - 1) * perPage
+	if err := query.Order("created_at DESC").Offset(offset).Limit(perPage).Find(&diffs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch shadow diffs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(diffs, page, perPage, total))
+}
+
+// ForJob handles GET /jobs/{id}/shadow-diffs - get shadow differences recorded for a job
+func (c *ShadowController) ForJob(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	jobID := chi.URLParam(r, "id")
+
+	var diffs []models.ShadowDiff
+	if err := config.DB.Where("job_id = ? AND user_id = ?", jobID, userID).
+		Order("created_at DESC").
+		Find(&diffs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch shadow diffs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Shadow diffs retrieved", diffs))
+}
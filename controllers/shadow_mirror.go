@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/models"
+	"janus-backend-api/signing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	shadowTimeout         = 30 * time.Second
+	maxShadowResponseSize = 10 << 20
+)
+
+// shadowMirror sends sampled copies of submissions to a candidate Janus deployment
+type shadowMirror struct {
+	baseURL    string
+	sampleRate float64
+	httpClient *http.Client
+	signer     *signing.Signer
+}
+
+// newShadowMirror returns nil when mirroring is not configured
+func newShadowMirror(baseURL string, sampleRate float64, signer *signing.Signer) *shadowMirror {
+	if baseURL == "" || sampleRate <= 0 {
+		return nil
+	}
+	if sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &shadowMirror{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		sampleRate: sampleRate,
+		httpClient: &http.Client{Timeout: shadowTimeout},
+		signer:     signer,
+	}
+}
+
+// sampled reports whether the current submission should be mirrored
+func (m *shadowMirror) sampled() bool {
+	return m != nil && rand.Float64() < m.sampleRate
+}
+
+// admissionDecision is a single job outcome extracted from a Janus response
+type admissionDecision struct {
+	JobID  string
+	Status string
+	Reason string
+}
+
+// mirror replays the submission against the shadow and records decision differences.
+// It takes ownership of body and closes it.
+func (m *shadowMirror) mirror(userID uuid.UUID, path string, body io.ReadCloser, size int64, digest string, primaryStatus int, primaryBody []byte) {
+	req, err := http.NewRequest(http.MethodPost, m.baseURL+path, body)
+	if err != nil {
+		body.Close()
+		log.Printf("Shadow: failed to create request: %v", err)
+		return
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signing.HeaderUserID, userID.String())
+	req.Header.Set("X-Janus-Shadow", "true")
+	if m.signer != nil {
+		if err := m.signer.Sign(req, digest); err != nil {
+			body.Close()
+			log.Printf("Shadow: failed to sign request: %v", err)
+			return
+		}
+	}
+
+	shadowStatus := 0
+	var shadowBody []byte
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		log.Printf("Shadow: request to %s failed: %v", path, err)
+	} else {
+		shadowStatus = resp.StatusCode
+		shadowBody, _ = io.ReadAll(io.LimitReader(resp.Body, maxShadowResponseSize))
+		resp.Body.Close()
+	}
+
+	diffs := compareDecisions(extractDecisions(primaryBody), extractDecisions(shadowBody), primaryStatus, shadowStatus)
+	for i := range diffs {
+		diffs[i].DiffID = uuid.New()
+		diffs[i].UserID = userID
+		diffs[i].Endpoint = path
+		diffs[i].CreatedAt = time.Now()
+	}
+	if len(diffs) == 0 {
+		return
+	}
+	if err := config.DB.Create(&diffs).Error; err != nil {
+		log.Printf("Shadow: failed to record %d diffs: %v", len(diffs), err)
+	}
+}
+
+// compareDecisions pairs decisions by position, since the shadow assigns its own job IDs
+func compareDecisions(primary, shadow []admissionDecision, primaryStatus, shadowStatus int) []models.ShadowDiff {
+	var diffs []models.ShadowDiff
+
+	// Without per-job decisions on either side, fall back to the HTTP outcome.
+	// There are no job statuses to record, only the status codes, unless the
+	// shadow could not be reached at all.
+	if len(primary) == 0 && len(shadow) == 0 {
+		if primaryStatus != shadowStatus {
+			diff := models.ShadowDiff{
+				PrimaryHTTPStatus: primaryStatus,
+				ShadowHTTPStatus:  shadowStatus,
+			}
+			if shadowStatus == 0 {
+				status := "error"
+				diff.ShadowStatus = &status
+			}
+			diffs = append(diffs, diff)
+		}
+		return diffs
+	}
+
+	n := max(len(primary), len(shadow))
+	for i := 0; i < n; i++ {
+		p := admissionDecision{Status: "missing"}
+		if i < len(primary) {
+			p = primary[i]
+		}
+		s := admissionDecision{Status: "missing"}
+		if i < len(shadow) {
+			s = shadow[i]
+		} else if shadowStatus == 0 {
+			s.Status = "error"
+		}
+		if p.Status == s.Status {
+			continue
+		}
+
+		diff := models.ShadowDiff{
+			PrimaryStatus:     &p.Status,
+			ShadowStatus:      &s.Status,
+			PrimaryHTTPStatus: primaryStatus,
+			ShadowHTTPStatus:  shadowStatus,
+		}
+		if p.JobID != "" {
+			jobID := p.JobID
+			diff.JobID = &jobID
+		}
+		if p.Reason != "" {
+			reason := p.Reason
+			diff.PrimaryReason = &reason
+		}
+		if s.Reason != "" {
+			reason := s.Reason
+			diff.ShadowReason = &reason
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// extractDecisions walks a Janus response and collects every object that carries a job decision.
+// Object keys are visited in sorted order so primary and shadow responses line up.
+func extractDecisions(body []byte) []admissionDecision {
+	var doc interface{}
+	if len(body) == 0 || json.Unmarshal(body, &doc) != nil {
+		return nil
+	}
+
+	var decisions []admissionDecision
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch node := v.(type) {
+		case []interface{}:
+			for _, item := range node {
+				walk(item)
+			}
+		case map[string]interface{}:
+			if jobID, ok := node["job_id"].(string); ok {
+				status := stringField(node, "job_status", "status")
+				if status != "" {
+					decisions = append(decisions, admissionDecision{
+						JobID:  jobID,
+						Status: strings.ToLower(status),
+						Reason: stringField(node, "reason"),
+					})
+					return
+				}
+			}
+			keys := make([]string, 0, len(node))
+			for k := range node {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(node[k])
+			}
+		}
+	}
+	walk(doc)
+	return decisions
+}
+
+// stringField returns the first non-empty string value among keys
+func stringField(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShareController handles read-only sharing of configs with users and orgs
+type ShareController struct{}
+
+// NewShareController creates a new ShareController
+func NewShareController() *ShareController {
+	return &ShareController{}
+}
+
+// List handles GET /configs/{id}/shares - list who a config is shared with (owner only)
+func (c *ShareController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	var shares []models.ConfigShare
+	if err := config.DB.Where("config_id = ?", configID).Order("created_at").Find(&shares).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch shares")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Shares retrieved", shares))
+}
+
+// Create handles POST /configs/{id}/shares - share a config with a user or an org (owner only)
+func (c *ShareController) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var req models.CreateShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	if (req.Email == "") == (req.OrgID == nil) {
+		respondError(w, r, models.ErrValidationFailed, "Set exactly one of email or org_id",
+			models.FieldError{Field: "email", Message: "exactly one of email or org_id is required"})
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	share := models.ConfigShare{
+		ShareID:   uuid.New(),
+		ConfigID:  configID,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	existing := config.DB.Where("config_id = ?", configID)
+	if req.Email != "" {
+		var user models.User
+		if err := config.DB.Where("LOWER(email) = LOWER(?)", req.Email).First(&user).Error; err != nil {
+			respondError(w, r, models.ErrUserNotFound, "No user with this email")
+			return
+		}
+		if user.UserID == userID {
+			respondError(w, r, models.ErrValidationFailed, "A config cannot be shared with its owner", models.FieldError{Field: "email", Message: "must belong to another user"})
+			return
+		}
+		share.UserID = &user.UserID
+		existing = existing.Where("user_id = ?", user.UserID)
+	} else {
+		// Owners can only share with orgs they belong to
+		if !isOrgMember(config.DB, *req.OrgID, userID) {
+			respondError(w, r, models.ErrOrgNotFound, "Org not found")
+			return
+		}
+		share.OrgID = req.OrgID
+		existing = existing.Where("org_id = ?", *req.OrgID)
+	}
+
+	// Sharing twice with the same recipient returns the existing share
+	var current models.ConfigShare
+	if err := existing.First(&current).Error; err == nil {
+		respondJSON(w, http.StatusOK, models.NewSuccessResponse("Config already shared", current))
+		return
+	}
+	if err := config.DB.Create(&share).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to share config")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Config shared", share))
+}
+
+// Delete handles DELETE /configs/{id}/shares/{shareId} - stop sharing (owner only)
+func (c *ShareController) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+	shareID, err := uuid.Parse(chi.URLParam(r, "shareId"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid share ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	result := config.DB.Where("share_id = ? AND config_id = ?", shareID, configID).Delete(&models.ConfigShare{})
+	if result.Error != nil {
+		respondError(w, r, models.ErrInternal, "Failed to remove share")
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondError(w, r, models.ErrShareNotFound, "Share not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Share removed", nil))
+}
+
+// Shared handles GET /configs/shared - configs other users shared with the caller
+func (c *ShareController) Shared(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := sharedConfigs(config.DB, userID)
+
+	var total int64
+	query.Count(&total)
+
+	var configs []models.GlobalJobConfig
+	offset := (page - 1) * perPage
+	if err := query.Order("config_name").Order("config_id").Offset(offset).Limit(perPage).Find(&configs).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch shared configs")
+		return
+	}
+
+	// Direct shares take precedence over org shares when reporting how access was granted
+	ids := make([]uuid.UUID, len(configs))
+	for i := range configs {
+		ids[i] = configs[i].ConfigID
+	}
+	var direct []uuid.UUID
+	config.DB.Model(&models.ConfigShare{}).Where("config_id IN ? AND user_id = ?", ids, userID).Pluck("config_id", &direct)
+	directSet := make(map[uuid.UUID]bool, len(direct))
+	for _, id := range direct {
+		directSet[id] = true
+	}
+
+	responses := make([]models.SharedConfigResponse, len(configs))
+	for i := range configs {
+		responses[i] = sharedResponse(&configs[i], directSet[configs[i].ConfigID])
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(responses, page, perPage, total))
+}
+
+// GetShared handles GET /configs/shared/{id} - read a config shared with the caller
+func (c *ShareController) GetShared(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := sharedConfigs(config.DB, userID).Where("config_id = ?", configID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	var direct int64
+	config.DB.Model(&models.ConfigShare{}).Where("config_id = ? AND user_id = ?", configID, userID).Count(&direct)
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Shared config retrieved", sharedResponse(&cfg, direct > 0)))
+}
+
+// sharedConfigs selects configs of other users shared with userID directly or
+// through an org userID is a member of
+func sharedConfigs(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	shares := db.Session(&gorm.Session{NewDB: true}).Model(&models.ConfigShare{}).Select("config_id").
+		Where("user_id = ? OR org_id IN (?)", userID, userOrgs(db, userID))
+	return db.Model(&models.GlobalJobConfig{}).Where("user_id <> ? AND config_id IN (?)", userID, shares)
+}
+
+// sharedResponse shows a shared config without the owner's delivery and approval state
+func sharedResponse(cfg *models.GlobalJobConfig, direct bool) models.SharedConfigResponse {
+	resp := models.SharedConfigResponse{
+		ConfigResponse: cfg.ToResponse(),
+		OwnerID:        cfg.UserID,
+		SharedVia:      models.SharedWithOrg,
+	}
+	if direct {
+		resp.SharedVia = models.SharedWithUser
+	}
+	resp.Sync = nil
+	resp.Approval = nil
+	return resp
+}
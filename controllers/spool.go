@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"sync"
+)
+
+// spoolMemoryThreshold is the largest body kept in memory; anything bigger goes to a temp file
+const spoolMemoryThreshold = 1 << 20
+
+// spooledBody is a request body captured once so it can be hashed before sending
+// and replayed to more than one upstream, without holding large bodies in memory
+type spooledBody struct {
+	digest string
+	size   int64
+	mem    []byte
+	file   *os.File
+}
+
+// spoolBody drains r, computing its SHA-256 as it goes
+func spoolBody(r io.Reader) (*spooledBody, error) {
+	hasher := sha256.New()
+	tee := io.TeeReader(r, hasher)
+
+	// Read up to the threshold into memory first; most submissions stop here
+	head, err := io.ReadAll(io.LimitReader(tee, spoolMemoryThreshold+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(head) <= spoolMemoryThreshold {
+		return &spooledBody{
+			digest: hex.EncodeToString(hasher.Sum(nil)),
+			size:   int64(len(head)),
+			mem:    head,
+		}, nil
+	}
+
+	file, err := os.CreateTemp("", "janus-submit-*")
+	if err != nil {
+		return nil, err
+	}
+	spool := &spooledBody{file: file}
+	if _, err := file.Write(head); err != nil {
+		spool.Close()
+		return nil, err
+	}
+	rest, err := io.Copy(file, tee)
+	if err != nil {
+		spool.Close()
+		return nil, err
+	}
+	spool.size = int64(len(head)) + rest
+	spool.digest = hex.EncodeToString(hasher.Sum(nil))
+	return spool, nil
+}
+
+// Open returns an independent reader positioned at the start of the body.
+// Readers opened before Close remain usable after it.
+func (s *spooledBody) Open() (io.ReadCloser, error) {
+	if s.file == nil {
+		return io.NopCloser(bytes.NewReader(s.mem)), nil
+	}
+	return os.Open(s.file.Name())
+}
+
+// Close releases the spool and removes any temp file
+func (s *spooledBody) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
+
+// hashingReader hashes and counts bytes as they are streamed through it. The
+// transport may still be reading when the response arrives, so the hash and
+// count are guarded by mu.
+type hashingReader struct {
+	r    io.Reader
+	mu   sync.Mutex
+	hash hash.Hash
+	n    int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, hash: sha256.New()}
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.mu.Lock()
+	h.hash.Write(p[:n])
+	h.n += int64(n)
+	h.mu.Unlock()
+	return n, err
+}
+
+// Digest returns the hex SHA-256 and the number of the bytes read so far
+func (h *hashingReader) Digest() (string, int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return hex.EncodeToString(h.hash.Sum(nil)), h.n
+}
+
+// cappedBuffer keeps at most limit bytes of what is written to it
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func newCappedBuffer(limit int) *cappedBuffer {
+	return &cappedBuffer{limit: limit}
+}
+
+// Write never fails, so it is safe to use in an io.MultiWriter next to the client response
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// Bytes returns the captured data
+func (b *cappedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
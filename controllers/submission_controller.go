@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// batchIDScanSize is how much of a Janus response is searched for the batch ID
+const batchIDScanSize = 64 << 10
+
+var batchIDPattern = regexp.MustCompile(`"batch_id"\s*:\s*"([^"\\]+)"`)
+
+// SubmissionController handles viewing of locally recorded submission attempts
+type SubmissionController struct{}
+
+// NewSubmissionController creates a new SubmissionController
+func NewSubmissionController() *SubmissionController {
+	return &SubmissionController{}
+}
+
+// List handles GET /submissions - list submission attempts with filtering
+func (c *SubmissionController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	// Build query
+	q := r.URL.Query()
+	query := config.DB.Model(&models.Submission{}).Where("user_id = ?", userID)
+	if endpoint := q.Get("endpoint"); endpoint != "" {
+		query = query.Where("endpoint = ?", endpoint)
+	}
+	if batchID := q.Get("batch_id"); batchID != "" {
+		query = query.Where("batch_id = ?", batchID)
+	}
+	if hash := q.Get("request_hash"); hash != "" {
+		query = query.Where("request_hash = ?", hash)
+	}
+	if status := q.Get("janus_status"); status != "" {
+		code, err := strconv.Atoi(status)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid janus_status", models.FieldError{Field: "janus_status", Message: "must be an integer"})
+			return
+		}
+		query = query.Where("janus_status = ?", code)
+	}
+	switch q.Get("outcome") {
+	case "":
+	case "success":
+		query = query.Where("janus_status BETWEEN 200 AND 299")
+	case "failure":
+		query = query.Where("(janus_status IS NULL OR janus_status NOT BETWEEN 200 AND 299)")
+	default:
+		respondError(w, r, models.ErrInvalidQueryParam, "outcome must be success or failure", models.FieldError{Field: "outcome", Message: "must be success or failure"})
+		return
+	}
+	if from := q.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid from time, use RFC 3339", models.FieldError{Field: "from", Message: "must be an RFC 3339 timestamp"})
+			return
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if to := q.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			respondError(w, r, models.ErrInvalidQueryParam, "Invalid to time, use RFC 3339", models.FieldError{Field: "to", Message: "must be an RFC 3339 timestamp"})
+			return
+		}
+		query = query.Where("created_at < ?", t)
+	}
+
+	// Count total
+	var total int64
+	query.Count(&total)
+
+	// Fetch submissions
+	var submissions []models.Submission
+	offset := (page - 1) * perPage
+	if err := query.Order("created_at DESC").Offset(offset).Limit(perPage).Find(&submissions).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch submissions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(submissions, page, perPage, total))
+}
+
+// Get handles GET /submissions/{id} - get a single submission attempt
+func (c *SubmissionController) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	submissionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid submission ID")
+		return
+	}
+
+	var submission models.Submission
+	if err := config.DB.Where("submission_id = ? AND user_id = ?", submissionID, userID).First(&submission).Error; err != nil {
+		respondError(w, r, models.ErrSubmissionNotFound, "Submission not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Submission retrieved", submission))
+}
+
+// recordSubmission stores an attempt without holding up the response
+func recordSubmission(attempt *models.Submission) {
+	go func() {
+		if err := config.DB.Create(attempt).Error; err != nil {
+			log.Printf("Failed to record submission %s: %v", attempt.SubmissionID, err)
+		}
+	}()
+}
+
+// findBatchID extracts the batch ID from the head of a Janus response
+func findBatchID(body []byte) *string {
+	match := batchIDPattern.FindSubmatch(body)
+	if match == nil {
+		return nil
+	}
+	batchID := string(match[1])
+	return &batchID
+}
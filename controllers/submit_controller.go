@@ -1,26 +1,49 @@
 package controllers
 
 import (
-	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"time"
 
+	"janus-backend-api/config"
 	"janus-backend-api/middleware"
 	"janus-backend-api/models"
+	"janus-backend-api/signing"
+
+	"github.com/google/uuid"
 )
 
 // SubmitController handles job submission proxy to Janus
 type SubmitController struct {
 	janusBaseURL string
 	httpClient   *http.Client
+	maxBodyBytes int64
+	signer       *signing.Signer
+	shadow       *shadowMirror
 }
 
 // NewSubmitController creates a new SubmitController
-func NewSubmitController(janusBaseURL string) *SubmitController {
+func NewSubmitController(cfg *config.AppConfig) *SubmitController {
+	signer := newJanusSigner(cfg)
 	return &SubmitController{
-		janusBaseURL: janusBaseURL,
+		janusBaseURL: cfg.JanusBaseURL,
 		httpClient:   &http.Client{},
+		maxBodyBytes: cfg.MaxSubmitBodyBytes,
+		signer:       signer,
+		shadow:       newShadowMirror(cfg.ShadowJanusURL, cfg.ShadowSampleRate, signer),
+	}
+}
+
+// newJanusSigner returns nil when no signing secret is configured
+func newJanusSigner(cfg *config.AppConfig) *signing.Signer {
+	if cfg.JanusSigningSecret == "" {
+		log.Println("⚠️  JANUS_SIGNING_SECRET not set - requests to Janus will not be signed")
+		return nil
 	}
+	return signing.NewSigner(cfg.JanusSigningKeyID, []byte(cfg.JanusSigningSecret))
 }
 
 // SubmitJob handles POST /submit/job - proxies to Janus /dashboard/jobs
@@ -38,53 +61,168 @@ func (c *SubmitController) SubmitBatchAtomic(w http.ResponseWriter, r *http.Requ
 	c.proxyToJanus(w, r, "/dashboard/jobs/batch/atomic")
 }
 
-// proxyToJanus forwards the request to the Janus microservice
+// proxiedResponseHeaders are copied from the Janus response to the client
+var proxiedResponseHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Location",
+	"Retry-After",
+	"X-Request-ID",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+}
+
+// proxyToJanus streams the request to the Janus microservice and the response back.
+// The body is only spooled when it has to be hashed for signing or replayed to the shadow.
+// Every attempt is recorded in the submissions table, whatever Janus answers.
 func (c *SubmitController) proxyToJanus(w http.ResponseWriter, r *http.Request, path string) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
-		respondJSON(w, http.StatusUnauthorized, models.NewErrorResponse("User not authenticated"))
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
 		return
 	}
 
-	// Read the request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		respondJSON(w, http.StatusBadRequest, models.NewErrorResponse("Failed to read request body"))
+	attempt := &models.Submission{
+		SubmissionID: uuid.New(),
+		UserID:       userID,
+		Endpoint:     path,
+		CreatedAt:    time.Now(),
+	}
+	defer recordSubmission(attempt)
+
+	if respondIfPaused(w, r, userID) {
+		attempt.Fail(errServicePaused)
 		return
 	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, c.maxBodyBytes)
 	defer r.Body.Close()
 
-	// Create proxy request
+	mirror := c.shadow.sampled()
+
+	var body io.Reader
+	var spool *spooledBody
+	var streamed *hashingReader
+	if c.signer != nil || mirror {
+		var err error
+		spool, err = spoolBody(r.Body)
+		if err != nil {
+			attempt.Fail(err)
+			c.respondBodyError(w, r, err)
+			return
+		}
+		defer spool.Close()
+		attempt.SizeBytes = spool.size
+		attempt.RequestHash = &spool.digest
+
+		spooled, err := spool.Open()
+		if err != nil {
+			attempt.Fail(err)
+			respondError(w, r, models.ErrInternal, "Failed to read request body")
+			return
+		}
+		body = spooled
+	} else {
+		streamed = newHashingReader(r.Body)
+		body = streamed
+	}
+
+	// Create proxy request; cancelling the client request cancels the call to Janus
 	proxyURL := c.janusBaseURL + path
-	proxyReq, err := http.NewRequest(http.MethodPost, proxyURL, bytes.NewReader(body))
+	proxyReq, err := http.NewRequestWithContext(r.Context(), http.MethodPost, proxyURL, body)
 	if err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to create proxy request"))
+		attempt.Fail(err)
+		respondError(w, r, models.ErrInternal, "Failed to create proxy request")
 		return
 	}
+	if spool != nil {
+		proxyReq.ContentLength = spool.size
+	}
 
 	// Set headers
 	proxyReq.Header.Set("Content-Type", "application/json")
-	proxyReq.Header.Set("X-User-ID", userID.String())
+	proxyReq.Header.Set(signing.HeaderUserID, userID.String())
+	if requestID := middleware.GetRequestID(r); requestID != "" {
+		proxyReq.Header.Set(middleware.RequestIDHeader, requestID)
+	}
+	if c.signer != nil {
+		if err := c.signer.Sign(proxyReq, spool.digest); err != nil {
+			attempt.Fail(err)
+			respondError(w, r, models.ErrInternal, "Failed to sign proxy request")
+			return
+		}
+	}
 
 	// Forward the request
+	sentAt := time.Now()
 	resp, err := c.httpClient.Do(proxyReq)
+	if streamed != nil {
+		digest, size := streamed.Digest()
+		attempt.SizeBytes = size
+		attempt.RequestHash = &digest
+	}
 	if err != nil {
-		respondJSON(w, http.StatusBadGateway, models.NewErrorResponse("Failed to connect to Janus service: "+err.Error()))
+		attempt.Fail(err)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			c.respondBodyError(w, r, maxBytesErr)
+		case r.Context().Err() != nil:
+			log.Printf("Submit to %s cancelled by client: %v", path, r.Context().Err())
+		default:
+			respondError(w, r, models.ErrJanusUnavailable, "Failed to connect to Janus service: "+err.Error())
+		}
 		return
 	}
 	defer resp.Body.Close()
+	attempt.JanusStatus = &resp.StatusCode
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	// Forward the response
+	for _, key := range proxiedResponseHeaders {
+		if value := resp.Header.Get(key); value != "" {
+			w.Header().Set(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	// Keep the head of the response to find the batch ID, or all of it when it has to be compared with the shadow
+	captured := newCappedBuffer(batchIDScanSize)
+	if mirror {
+		captured = newCappedBuffer(maxShadowResponseSize)
+	}
+	_, err = io.Copy(io.MultiWriter(w, captured), resp.Body)
+	attempt.LatencyMs = time.Since(sentAt).Milliseconds()
+	attempt.BatchID = findBatchID(captured.Bytes())
 	if err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.NewErrorResponse("Failed to read Janus response"))
+		attempt.Fail(err)
+		log.Printf("Failed to stream Janus response for %s: %v", path, err)
 		return
 	}
 
-	// Forward the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(resp.StatusCode)
-	w.Write(respBody)
+	// Mirror the submission to the shadow deployment; its response is never returned
+	if mirror {
+		if captured.truncated {
+			log.Printf("Shadow: primary response for %s too large to compare, skipping", path)
+			return
+		}
+		shadowBody, err := spool.Open()
+		if err != nil {
+			log.Printf("Shadow: failed to reopen request body: %v", err)
+			return
+		}
+		go c.shadow.mirror(userID, path, shadowBody, spool.size, spool.digest, resp.StatusCode, captured.Bytes())
+	}
+}
+
+// respondBodyError reports a failure to read the submission body
+func (c *SubmitController) respondBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondError(w, r, models.ErrPayloadTooLarge, fmt.Sprintf("Request body exceeds the %d byte limit", maxBytesErr.Limit))
+		return
+	}
+	respondError(w, r, models.ErrInvalidRequestBody, "Failed to read request body")
 }
 
 // SubmitJobRequest represents a job submission request
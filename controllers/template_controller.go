@@ -0,0 +1,515 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"janus-backend-api/config"
+	"janus-backend-api/middleware"
+	"janus-backend-api/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TemplateController handles the template gallery: publishing configs as
+// templates, browsing them, instantiating them and pulling upstream changes
+type TemplateController struct{}
+
+// NewTemplateController creates a new TemplateController
+func NewTemplateController() *TemplateController {
+	return &TemplateController{}
+}
+
+// Publish handles POST /configs/{id}/publish - publish a config to the gallery.
+// The first publish creates the template; later ones add a version when the config changed.
+func (c *TemplateController) Publish(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var req models.PublishTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+
+	now := time.Now()
+	var template models.ConfigTemplate
+	err = tx.Where("source_config_id = ?", configID).First(&template).Error
+	created := errors.Is(err, gorm.ErrRecordNotFound)
+	switch {
+	case created:
+		template = models.ConfigTemplate{
+			TemplateID:     uuid.New(),
+			Name:           req.Name,
+			Description:    req.Description,
+			OwnerID:        userID,
+			SourceConfigID: &configID,
+			CreatedAt:      now,
+		}
+		if template.Name == "" {
+			template.Name = derefString(cfg.ConfigName)
+		}
+	case err != nil:
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to publish template")
+		return
+	default:
+		if req.Name != "" {
+			template.Name = req.Name
+		}
+		if req.Description != "" {
+			template.Description = req.Description
+		}
+	}
+
+	if template.Name == "" {
+		tx.Rollback()
+		respondError(w, r, models.ErrValidationFailed, "Template name is required", models.FieldError{Field: "name", Message: "is required"})
+		return
+	}
+	var taken int64
+	tx.Model(&models.ConfigTemplate{}).Where("LOWER(name) = LOWER(?) AND template_id <> ?", template.Name, template.TemplateID).Count(&taken)
+	if taken > 0 {
+		tx.Rollback()
+		respondError(w, r, models.ErrTemplateNameTaken, "A template with this name already exists", models.FieldError{Field: "name", Message: "is already taken"})
+		return
+	}
+
+	// Only a changed config becomes a new version; metadata edits do not
+	publishVersion := created
+	if !created {
+		latest, err := findTemplateVersion(tx, template.TemplateID, template.LatestVersion)
+		if err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to publish template")
+			return
+		}
+		publishVersion = len(models.DiffConfigs(latest.Config, cfg.Config)) > 0
+	}
+
+	template.Labels = cfg.Labels
+	template.UpdatedAt = now
+	var version models.ConfigTemplateVersion
+	if publishVersion {
+		template.LatestVersion++
+		version = models.ConfigTemplateVersion{
+			TemplateID:    template.TemplateID,
+			Version:       template.LatestVersion,
+			Config:        cfg.Config,
+			SourceVersion: cfg.CurrentVersion,
+			Changelog:     req.Changelog,
+			PublishedAt:   now,
+		}
+		if err := tx.Create(&version).Error; err != nil {
+			tx.Rollback()
+			respondError(w, r, models.ErrInternal, "Failed to publish template")
+			return
+		}
+	}
+	if err := tx.Save(&template).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to publish template")
+		return
+	}
+	tx.Commit()
+
+	resp := models.TemplateResponse{ConfigTemplate: template, Config: cfg.Config}
+	switch {
+	case created:
+		respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Template published", resp))
+	case publishVersion:
+		respondJSON(w, http.StatusOK, models.NewSuccessResponse("Template version "+strconv.Itoa(version.Version)+" published", resp))
+	default:
+		respondJSON(w, http.StatusOK, models.NewSuccessResponse("Template already up to date", resp))
+	}
+}
+
+// List handles GET /templates - browse the gallery (?q=&label=&owner=me, paginated)
+func (c *TemplateController) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	// Parse pagination params
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	q := r.URL.Query()
+	query := config.DB.Model(&models.ConfigTemplate{})
+	if search := q.Get("q"); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("(name ILIKE ? OR description ILIKE ?)", pattern, pattern)
+	}
+	if labels := q["label"]; len(labels) > 0 {
+		encoded, _ := json.Marshal(labels)
+		query = query.Where("labels @> ?::jsonb", string(encoded))
+	}
+	switch q.Get("owner") {
+	case "":
+	case "me":
+		query = query.Where("owner_id = ?", userID)
+	default:
+		respondError(w, r, models.ErrInvalidQueryParam, "owner must be me", models.FieldError{Field: "owner", Message: "must be me"})
+		return
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var templates []models.ConfigTemplate
+	offset := (page - 1) * perPage
+	if err := query.Order("updated_at DESC").Order("template_id").Offset(offset).Limit(perPage).Find(&templates).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch templates")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewPaginatedResponse(templates, page, perPage, total))
+}
+
+// Get handles GET /templates/{id} - a template with the config of its latest version
+func (c *TemplateController) Get(w http.ResponseWriter, r *http.Request) {
+	if _, ok := middleware.GetUserID(r); !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	template, ok := c.loadTemplate(w, r)
+	if !ok {
+		return
+	}
+	latest, err := findTemplateVersion(config.DB, template.TemplateID, template.LatestVersion)
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch template")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Template retrieved", models.TemplateResponse{ConfigTemplate: *template, Config: latest.Config}))
+}
+
+// Versions handles GET /templates/{id}/versions - published versions, newest first
+func (c *TemplateController) Versions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := middleware.GetUserID(r); !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	template, ok := c.loadTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	var versions []models.ConfigTemplateVersion
+	if err := config.DB.Where("template_id = ?", template.TemplateID).Order("version DESC").Find(&versions).Error; err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch template versions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Template versions retrieved", versions))
+}
+
+// GetVersion handles GET /templates/{id}/versions/{version} - one published version
+func (c *TemplateController) GetVersion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := middleware.GetUserID(r); !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	template, ok := c.loadTemplate(w, r)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		respondError(w, r, models.ErrInvalidID, "Invalid version")
+		return
+	}
+
+	published, err := findTemplateVersion(config.DB, template.TemplateID, version)
+	if err != nil {
+		respondError(w, r, models.ErrVersionNotFound, "Template version not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Template version retrieved", published))
+}
+
+// Delete handles DELETE /templates/{id} - remove a template from the gallery (owner only).
+// Configs instantiated from it are kept but can no longer pull updates.
+func (c *TemplateController) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid template ID")
+		return
+	}
+
+	tx := config.DB.Begin()
+	result := tx.Where("template_id = ? AND owner_id = ?", templateID, userID).Delete(&models.ConfigTemplate{})
+	if result.Error != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to delete template")
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		respondError(w, r, models.ErrTemplateNotFound, "Template not found")
+		return
+	}
+	if err := tx.Where("template_id = ?", templateID).Delete(&models.ConfigTemplateVersion{}).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to delete template")
+		return
+	}
+	tx.Commit()
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Template deleted", nil))
+}
+
+// Instantiate handles POST /templates/{id}/instantiate - create an inactive config from a template version
+func (c *TemplateController) Instantiate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	var req models.InstantiateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	template, ok := c.loadTemplate(w, r)
+	if !ok {
+		return
+	}
+	version := req.Version
+	if version == 0 {
+		version = template.LatestVersion
+	}
+	published, err := findTemplateVersion(config.DB, template.TemplateID, version)
+	if err != nil {
+		respondError(w, r, models.ErrVersionNotFound, "Template version not found")
+		return
+	}
+
+	name := req.ConfigName
+	if name == "" {
+		name = template.Name
+	}
+	cfg := models.GlobalJobConfig{
+		ConfigID:        uuid.New(),
+		UserID:          userID,
+		ConfigName:      &name,
+		Config:          published.Config,
+		Status:          models.ConfigStatusInactive,
+		Labels:          template.Labels,
+		CreatedBy:       &userID,
+		TemplateID:      &template.TemplateID,
+		TemplateVersion: &published.Version,
+	}
+
+	tx := config.DB.Begin()
+	if err := tx.Create(&cfg).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create config")
+		return
+	}
+	if err := appendRevision(tx, &cfg, userID, models.RevisionCreated, nil); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to create config")
+		return
+	}
+	tx.Commit()
+
+	setConfigETag(w, &cfg)
+	respondJSON(w, http.StatusCreated, models.NewSuccessResponse("Config created from template", cfg.ToResponse()))
+}
+
+// Upstream handles GET /configs/{id}/upstream - compare a config with its template
+func (c *TemplateController) Upstream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var cfg models.GlobalJobConfig
+	if err := config.DB.Where("config_id = ? AND user_id = ?", configID, userID).First(&cfg).Error; err != nil {
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if cfg.TemplateID == nil || cfg.TemplateVersion == nil {
+		respondError(w, r, models.ErrNoUpstreamTemplate, "Config was not created from a template")
+		return
+	}
+
+	var template models.ConfigTemplate
+	if err := config.DB.Where("template_id = ?", *cfg.TemplateID).First(&template).Error; err != nil {
+		respondError(w, r, models.ErrTemplateNotFound, "Template no longer exists")
+		return
+	}
+	base, err := findTemplateVersion(config.DB, template.TemplateID, *cfg.TemplateVersion)
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch template version")
+		return
+	}
+	latest, err := findTemplateVersion(config.DB, template.TemplateID, template.LatestVersion)
+	if err != nil {
+		respondError(w, r, models.ErrInternal, "Failed to fetch template version")
+		return
+	}
+
+	resp := models.UpstreamResponse{
+		ConfigID:        cfg.ConfigID,
+		TemplateID:      template.TemplateID,
+		TemplateName:    template.Name,
+		CurrentVersion:  base.Version,
+		LatestVersion:   latest.Version,
+		UpdateAvailable: latest.Version > base.Version,
+		UpstreamChanges: models.DiffConfigs(base.Config, latest.Config),
+		Changes:         models.DiffConfigs(cfg.Config, latest.Config),
+	}
+
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Upstream retrieved", resp))
+}
+
+// Pull handles POST /configs/{id}/upstream/pull - replace a config with a template version,
+// the latest by default. Local edits are overwritten; the previous content stays in the revisions.
+func (c *TemplateController) Pull(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondError(w, r, models.ErrUnauthenticated, "User not authenticated")
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid config ID")
+		return
+	}
+
+	var req models.PullTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, r, models.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	tx := config.DB.Begin()
+	cfg, err := lockUserConfig(tx, userID, configID)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrConfigNotFound, "Config not found")
+		return
+	}
+	if !checkIfMatch(w, r, cfg, false) {
+		tx.Rollback()
+		return
+	}
+	if !requireNoApproval(w, r, cfg) {
+		tx.Rollback()
+		return
+	}
+	if cfg.TemplateID == nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrNoUpstreamTemplate, "Config was not created from a template")
+		return
+	}
+
+	var template models.ConfigTemplate
+	if err := tx.Where("template_id = ?", *cfg.TemplateID).First(&template).Error; err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrTemplateNotFound, "Template no longer exists")
+		return
+	}
+	version := req.Version
+	if version == 0 {
+		version = template.LatestVersion
+	}
+	published, err := findTemplateVersion(tx, template.TemplateID, version)
+	if err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrVersionNotFound, "Template version not found")
+		return
+	}
+
+	cfg.Config = published.Config
+	cfg.TemplateVersion = &published.Version
+	if err := appendRevision(tx, cfg, userID, models.RevisionTemplatePulled, nil); err != nil {
+		tx.Rollback()
+		respondError(w, r, models.ErrInternal, "Failed to update config")
+		return
+	}
+	tx.Commit()
+	requestConfigSync()
+
+	setConfigETag(w, cfg)
+	respondJSON(w, http.StatusOK, models.NewSuccessResponse("Template version "+strconv.Itoa(published.Version)+" pulled", cfg.ToResponse()))
+}
+
+// loadTemplate reads the {id} template, responding with an error if it does not exist
+func (c *TemplateController) loadTemplate(w http.ResponseWriter, r *http.Request) (*models.ConfigTemplate, bool) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, models.ErrInvalidID, "Invalid template ID")
+		return nil, false
+	}
+	var template models.ConfigTemplate
+	if err := config.DB.Where("template_id = ?", templateID).First(&template).Error; err != nil {
+		respondError(w, r, models.ErrTemplateNotFound, "Template not found")
+		return nil, false
+	}
+	return &template, true
+}
+
+func findTemplateVersion(db *gorm.DB, templateID uuid.UUID, version int) (*models.ConfigTemplateVersion, error) {
+	var published models.ConfigTemplateVersion
+	if err := db.Where("template_id = ? AND version = ?", templateID, version).First(&published).Error; err != nil {
+		return nil, err
+	}
+	return &published, nil
+}
@@ -0,0 +1,136 @@
+// Package jobquery compiles the job payload filter language used by GET /jobs?q=
+// into a parameterized Postgres condition over job_payload.
+//
+// A query compares payload paths with literals:
+//
+//	custom_key = "abc" and (priority >= 5 or exists(meta.customer_id))
+//	tenant_id in ("t1", "t2") and not region = 'eu'
+//
+// Paths are dot-separated object keys and must be allowed by Options.Fields.
+// Literals are quoted strings, numbers, true, false and null. Nothing from the
+// query is ever written into the SQL text: keys and values are bind parameters,
+// and the SQL itself is assembled only from fixed fragments.
+package jobquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Default limits applied when Options leaves them unset
+const (
+	DefaultMaxLength    = 1024
+	DefaultMaxTerms     = 20
+	DefaultMaxDepth     = 8
+	DefaultMaxPathDepth = 8
+)
+
+// payload is the column queries run against. job_payload is stored as json, so
+// it is cast to jsonb for the operators; the payload index uses the same expression.
+const payload = "job_payload::jsonb"
+
+// Options controls which payload paths may be queried and how large a query may be
+type Options struct {
+	// Fields lists the queryable paths. "a.b" allows exactly that path, "a.*"
+	// allows a and everything below it, and "*" allows any path.
+	Fields []string
+
+	// MaxLength is the longest accepted query, in bytes
+	MaxLength int
+	// MaxTerms caps comparisons, counting every value of an in list
+	MaxTerms int
+	// MaxDepth caps nesting of parentheses and not
+	MaxDepth int
+	// MaxPathDepth caps the number of keys in a path
+	MaxPathDepth int
+}
+
+// Error is a problem with a query at a byte offset
+type Error struct {
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos)
+}
+
+// Filter is a compiled query, ready for db.Where(f.SQL, f.Args...)
+type Filter struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Compile parses src and returns the equivalent SQL condition
+func Compile(src string, opts Options) (*Filter, error) {
+	opts = opts.withDefaults()
+	if len(src) > opts.MaxLength {
+		return nil, &Error{Pos: opts.MaxLength, Message: fmt.Sprintf("query is longer than %d characters", opts.MaxLength)}
+	}
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, opts: opts}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &Error{Pos: t.pos, Message: "unexpected " + t.String()}
+	}
+
+	b := &builder{}
+	root.build(b)
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &Filter{SQL: b.sql.String(), Args: b.args}, nil
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxLength <= 0 {
+		o.MaxLength = DefaultMaxLength
+	}
+	if o.MaxTerms <= 0 {
+		o.MaxTerms = DefaultMaxTerms
+	}
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = DefaultMaxDepth
+	}
+	if o.MaxPathDepth <= 0 {
+		o.MaxPathDepth = DefaultMaxPathDepth
+	}
+	return o
+}
+
+// allows reports whether path may be queried
+func (o Options) allows(path string) bool {
+	for _, field := range o.Fields {
+		if field == "*" || field == path {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(field, ".*"); ok && (path == prefix || strings.HasPrefix(path, prefix+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// literal is a JSON scalar written in a query
+type literal struct {
+	value interface{} // string, json.Number, bool or nil
+	pos   int
+}
+
+func (l literal) isNumber() bool {
+	_, ok := l.value.(json.Number)
+	return ok
+}
+
+func (l literal) isString() bool {
+	_, ok := l.value.(string)
+	return ok
+}
@@ -0,0 +1,178 @@
+package jobquery
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string // identifier, operator or number as written; decoded string
+	pos  int
+}
+
+// keyword reports whether t is the given keyword; keywords are case-insensitive
+func (t token) keyword(word string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, word)
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lex splits src into tokens. Paths are lexed as one identifier, dots included.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ",", i})
+			i++
+		case c == '=':
+			tokens = append(tokens, token{tokOp, "=", i})
+			i++
+		case c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(src) && src[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &Error{Pos: i, Message: "expected !="}
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		case c == '"' || c == '\'':
+			s, n, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokString, s, i})
+			i += n
+		case c == '-' || isDigit(c):
+			n := lexNumber(src, i)
+			if n == 0 {
+				return nil, &Error{Pos: i, Message: "invalid number"}
+			}
+			tokens = append(tokens, token{tokNumber, src[i : i+n], i})
+			i += n
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && (isIdentPart(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, src[start:i], start})
+		default:
+			return nil, &Error{Pos: i, Message: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+// lexString reads a quoted string starting at src[start]. Backslash escapes the
+// quote character and the backslash itself.
+func lexString(src string, start int) (string, int, error) {
+	quote := src[start]
+	var b strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if i+1 < len(src) && (src[i+1] == quote || src[i+1] == '\\') {
+				i++
+				b.WriteByte(src[i])
+				continue
+			}
+			return "", 0, &Error{Pos: i, Message: "invalid escape in string"}
+		case quote:
+			return b.String(), i - start + 1, nil
+		default:
+			b.WriteByte(src[i])
+		}
+	}
+	return "", 0, &Error{Pos: start, Message: "unterminated string"}
+}
+
+// lexNumber returns the length of the JSON number at src[start], or 0 if there
+// is none. As in JSON, the integer part has no leading zeros.
+func lexNumber(src string, start int) int {
+	i := start
+	if i < len(src) && src[i] == '-' {
+		i++
+	}
+	digits := i
+	for i < len(src) && isDigit(src[i]) {
+		i++
+	}
+	if i == digits || (src[digits] == '0' && i-digits > 1) {
+		return 0
+	}
+	if i < len(src) && src[i] == '.' {
+		i++
+		fraction := i
+		for i < len(src) && isDigit(src[i]) {
+			i++
+		}
+		if i == fraction {
+			return 0
+		}
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		i++
+		if i < len(src) && (src[i] == '+' || src[i] == '-') {
+			i++
+		}
+		exponent := i
+		for i < len(src) && isDigit(src[i]) {
+			i++
+		}
+		if i == exponent {
+			return 0
+		}
+	}
+	// A number running into a name, like 12abc, is not a number
+	if i < len(src) && isIdentPart(src[i]) {
+		return 0
+	}
+	return i - start
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '-'
+}
@@ -0,0 +1,231 @@
+package jobquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// reserved words cannot start a path
+var reserved = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true,
+	"true": true, "false": true, "null": true,
+}
+
+// parser is a recursive descent parser over the grammar
+//
+//	or      = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | "(" or ")" | "exists" "(" path ")" | path test
+//	test    = op value | [ "not" ] "in" "(" value { "," value } ")"
+//	op      = "=" | "!=" | "<" | "<=" | ">" | ">="
+type parser struct {
+	tokens []token
+	i      int
+	opts   Options
+	terms  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, &Error{Pos: t.pos, Message: fmt.Sprintf("expected %s, found %s", what, t)}
+	}
+	return t, nil
+}
+
+// term counts one comparison against the complexity limit
+func (p *parser) term(pos int) error {
+	p.terms++
+	if p.terms > p.opts.MaxTerms {
+		return &Error{Pos: pos, Message: fmt.Sprintf("query has more than %d comparisons", p.opts.MaxTerms)}
+	}
+	return nil
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("or") {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicNode{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("and") {
+		p.next()
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicNode{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary(depth int) (node, error) {
+	t := p.peek()
+	if depth > p.opts.MaxDepth {
+		return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("query is nested more than %d levels deep", p.opts.MaxDepth)}
+	}
+
+	switch {
+	case t.keyword("not"):
+		p.next()
+		inner, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{inner: inner}, nil
+	case t.kind == tokLParen:
+		p.next()
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case t.keyword("exists") && p.tokens[p.i+1].kind == tokLParen:
+		p.next()
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		if err := p.term(t.pos); err != nil {
+			return nil, err
+		}
+		return &existsNode{path: path}, nil
+	}
+
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseTest(path)
+}
+
+// parseTest parses what follows a path: a comparison or an in list
+func (p *parser) parseTest(path []string) (node, error) {
+	t := p.next()
+	if t.kind == tokOp {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if t.text != "=" && t.text != "!=" && !value.isNumber() && !value.isString() {
+			return nil, &Error{Pos: value.pos, Message: t.text + " needs a number or a string"}
+		}
+		if err := p.term(t.pos); err != nil {
+			return nil, err
+		}
+		return &compareNode{path: path, op: t.text, value: value}, nil
+	}
+
+	negate := false
+	if t.keyword("not") {
+		negate = true
+		t = p.next()
+	}
+	if !t.keyword("in") {
+		return nil, &Error{Pos: t.pos, Message: "expected a comparison or in, found " + t.String()}
+	}
+	if _, err := p.expect(tokLParen, "("); err != nil {
+		return nil, err
+	}
+	var values []literal
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.term(value.pos); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+
+	var n node = &inNode{path: path, values: values}
+	if negate {
+		n = &notNode{inner: n}
+	}
+	return n, nil
+}
+
+// parsePath reads a dot-separated path of object keys and checks it is allowed
+func (p *parser) parsePath() ([]string, error) {
+	t := p.next()
+	if t.kind != tokIdent || reserved[strings.ToLower(t.text)] {
+		return nil, &Error{Pos: t.pos, Message: "expected a payload path, found " + t.String()}
+	}
+	keys := strings.Split(t.text, ".")
+	if len(keys) > p.opts.MaxPathDepth {
+		return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("path %s has more than %d keys", t.text, p.opts.MaxPathDepth)}
+	}
+	// Keys must look like names: jsonb_extract_path would read 0 or -1 as an array index
+	for _, key := range keys {
+		if key == "" || !isIdentStart(key[0]) {
+			return nil, &Error{Pos: t.pos, Message: "invalid path " + t.text}
+		}
+	}
+	if !p.opts.allows(t.text) {
+		return nil, &Error{Pos: t.pos, Message: "path " + t.text + " cannot be queried"}
+	}
+	return keys, nil
+}
+
+func (p *parser) parseValue() (literal, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokString:
+		return literal{value: t.text, pos: t.pos}, nil
+	case t.kind == tokNumber:
+		return literal{value: json.Number(t.text), pos: t.pos}, nil
+	case t.keyword("true"):
+		return literal{value: true, pos: t.pos}, nil
+	case t.keyword("false"):
+		return literal{value: false, pos: t.pos}, nil
+	case t.keyword("null"):
+		return literal{value: nil, pos: t.pos}, nil
+	case t.kind == tokIdent:
+		return literal{}, &Error{Pos: t.pos, Message: "expected a value, found " + t.String() + "; quote strings"}
+	default:
+		return literal{}, &Error{Pos: t.pos, Message: "expected a value, found " + t.String()}
+	}
+}
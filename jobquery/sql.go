@@ -0,0 +1,141 @@
+package jobquery
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// node is a parsed query expression
+type node interface {
+	build(b *builder)
+}
+
+// builder accumulates SQL and its bind parameters. Only fixed fragments are
+// written as SQL; everything taken from the query goes through arg. err keeps
+// the first value that could not be encoded.
+type builder struct {
+	sql  strings.Builder
+	args []interface{}
+	err  error
+}
+
+func (b *builder) write(s string) {
+	b.sql.WriteString(s)
+}
+
+func (b *builder) arg(v interface{}) {
+	b.sql.WriteString("?")
+	b.args = append(b.args, v)
+}
+
+// extract writes the jsonb value at path, NULL when a key is missing
+func (b *builder) extract(path []string) {
+	b.write("jsonb_extract_path(" + payload)
+	for _, key := range path {
+		b.write(", ")
+		b.arg(key)
+	}
+	b.write(")")
+}
+
+// contains writes a containment test of path = value. Unlike comparing the
+// extracted value it can use the GIN index on the payload.
+func (b *builder) contains(path []string, l literal) {
+	value := l.value
+	for i := len(path) - 1; i >= 0; i-- {
+		value = map[string]interface{}{path[i]: value}
+	}
+	doc, err := json.Marshal(value)
+	if err != nil && b.err == nil {
+		b.err = &Error{Pos: l.pos, Message: "invalid value"}
+	}
+	b.write(payload + " @> ")
+	b.arg(string(doc))
+	b.write("::jsonb")
+}
+
+type logicNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicNode) build(b *builder) {
+	b.write("(")
+	n.left.build(b)
+	b.write(" " + n.op + " ")
+	n.right.build(b)
+	b.write(")")
+}
+
+type notNode struct {
+	inner node
+}
+
+func (n *notNode) build(b *builder) {
+	b.write("NOT (")
+	n.inner.build(b)
+	b.write(")")
+}
+
+type existsNode struct {
+	path []string
+}
+
+func (n *existsNode) build(b *builder) {
+	b.extract(n.path)
+	b.write(" IS NOT NULL")
+}
+
+// compareNode is path op value. The parser only lets numbers and strings reach
+// the range operators; they match values of the same JSON type and nothing else.
+type compareNode struct {
+	path  []string
+	op    string
+	value literal
+}
+
+func (n *compareNode) build(b *builder) {
+	switch n.op {
+	case "=":
+		b.contains(n.path, n.value)
+		return
+	case "!=":
+		b.write("NOT (")
+		b.contains(n.path, n.value)
+		b.write(")")
+		return
+	}
+
+	if n.value.isNumber() {
+		b.write("(CASE WHEN jsonb_typeof(")
+		b.extract(n.path)
+		b.write(") = 'number' THEN (")
+		b.extract(n.path)
+		b.write(")::numeric END) " + n.op + " ")
+		b.arg(string(n.value.value.(json.Number)))
+		b.write("::numeric")
+		return
+	}
+	b.write("(CASE WHEN jsonb_typeof(")
+	b.extract(n.path)
+	b.write(") = 'string' THEN ")
+	b.extract(n.path)
+	b.write(" #>> '{}' END) " + n.op + " ")
+	b.arg(n.value.value)
+}
+
+type inNode struct {
+	path   []string
+	values []literal
+}
+
+func (n *inNode) build(b *builder) {
+	b.write("(")
+	for i, value := range n.values {
+		if i > 0 {
+			b.write(" OR ")
+		}
+		b.contains(n.path, value)
+	}
+	b.write(")")
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"janus-backend-api/config"
+	"janus-backend-api/controllers"
 	"janus-backend-api/middleware"
 	"janus-backend-api/routes"
 )
@@ -20,35 +26,44 @@ func main() {
 	// Connect to database
 	config.ConnectDatabase()
 
-	// Run migrations for auth columns (if they don't exist)
-	runMigrations()
+	// Run schema migrations (if they don't exist)
+	config.RunMigrations()
+
+	// Background workers stop when the process is asked to shut down
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go controllers.NewScheduleEvaluator(cfg.ScheduleInterval).Run(ctx)
+	go controllers.NewConfigSyncer(cfg).Run(ctx)
+	go controllers.NewConfigPurger(cfg.ConfigTrashRetention).Run(ctx)
+	go controllers.NewExportRunner(cfg).Run(ctx)
+	go controllers.NewServiceResumer().Run(ctx)
 
 	// Setup router
-	router := routes.SetupRouter(cfg.JanusBaseURL)
+	router := routes.SetupRouter(cfg)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.ServerPort)
 	log.Printf("🚀 Janus API starting on http://localhost%s", addr)
 	log.Printf("📍 API Endpoints:")
 	log.Printf("   Auth:    /auth/register, /auth/login, /auth/profile")
-	log.Printf("   Submit:  /submit/job, /submit/batch, /submit/batch/atomic")
-	log.Printf("   Configs: /configs (CRUD + activate/deactivate)")
-	log.Printf("   Jobs:    /jobs, /jobs/stats, /jobs/{id}")
-	log.Printf("   Batches: /batches, /batches/{id}, /batches/{id}/jobs")
+	log.Printf("   Submit:  /submit/job, /submit/batch, /submit/batch/atomic, /submissions, /service (status/pause/resume)")
+	log.Printf("   Configs: /configs (CRUD + activate/deactivate), /configs/trash, /configs/schedules, /configs/activations, /configs/usage/check")
+	log.Printf("   Changes: /change-requests (approve/reject/comment/cancel)")
+	log.Printf("   Sharing: /configs/shared, /orgs, /templates (publish, instantiate, upstream pull)")
+	log.Printf("   Jobs:    /jobs, /jobs/stats, /jobs/stats/timeseries, /jobs/stats/reasons, /jobs/export, /jobs/{id}")
+	log.Printf("   Batches: /batches, /batches/{id}, /batches/{id}/jobs, /batches/{id}/export")
+	log.Printf("   Exports: /exports, /exports/{id}, /exports/{id}/download")
+	log.Printf("   Shadow:  /shadow/diffs, /jobs/{id}/shadow-diffs")
+
+	server := &http.Server{Addr: addr, Handler: router}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
 
-	if err := http.ListenAndServe(addr, router); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
-
-// runMigrations adds auth columns to users table if they don't exist
-func runMigrations() {
-	// Add email column if not exists
-	config.DB.Exec(`
-		ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT UNIQUE;
-		ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT;
-		ALTER TABLE users ADD COLUMN IF NOT EXISTS google_id TEXT UNIQUE;
-		ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT NOW();
-	`)
-	log.Println("✅ Database migrations complete")
-}
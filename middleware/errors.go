@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"janus-backend-api/models"
+)
+
+const problemContentType = "application/problem+json"
+
+// WriteError writes an error with the status from the error catalog. Clients that
+// prefer application/problem+json get an RFC 7807 problem, everyone else an APIResponse.
+func WriteError(w http.ResponseWriter, r *http.Request, code models.ErrorCode, message string, details ...models.FieldError) {
+	WriteErrorWithCurrent(w, r, code, message, nil, details...)
+}
+
+// WriteErrorWithCurrent writes an error that also carries the current state of the
+// resource, e.g. on a failed precondition. It is returned as "data", or "current" in a problem.
+func WriteErrorWithCurrent(w http.ResponseWriter, r *http.Request, code models.ErrorCode, message string, current interface{}, details ...models.FieldError) {
+	requestID := GetRequestID(r)
+	status := code.Status()
+
+	if wantsProblemJSON(r) {
+		problem := models.NewProblemDetails(code, message, r.URL.Path, requestID, details)
+		problem.Current = current
+		w.Header().Set("Content-Type", problemContentType)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(problem)
+		return
+	}
+
+	resp := models.NewErrorResponse(code, message, details, requestID)
+	resp.Data = current
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// NotFound responds to unknown routes
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, r, models.ErrRouteNotFound, "No route for "+r.Method+" "+r.URL.Path)
+}
+
+// MethodNotAllowed responds to known routes called with the wrong method
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, r, models.ErrMethodNotAllowed, "Method "+r.Method+" not allowed on "+r.URL.Path)
+}
+
+// wantsProblemJSON reports whether the Accept header ranks problem+json at least as high as plain JSON
+func wantsProblemJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return false
+	}
+
+	problemQ, jsonQ := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		switch mediaType {
+		case problemContentType:
+			problemQ = max(problemQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
@@ -2,7 +2,6 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"strings"
 	"time"
@@ -52,13 +51,13 @@ func JWTAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			respondError(w, http.StatusUnauthorized, "Authorization header required")
+			WriteError(w, r, models.ErrUnauthenticated, "Authorization header required")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			respondError(w, http.StatusUnauthorized, "Invalid authorization format. Use: Bearer <token>")
+			WriteError(w, r, models.ErrInvalidToken, "Invalid authorization format. Use: Bearer <token>")
 			return
 		}
 
@@ -70,7 +69,7 @@ func JWTAuth(next http.Handler) http.Handler {
 		})
 
 		if err != nil || !token.Valid {
-			respondError(w, http.StatusUnauthorized, "Invalid or expired token")
+			WriteError(w, r, models.ErrInvalidToken, "Invalid or expired token")
 			return
 		}
 
@@ -85,9 +84,3 @@ func GetUserID(r *http.Request) (uuid.UUID, bool) {
 	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
 	return userID, ok
 }
-
-func respondError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(models.NewErrorResponse(message))
-}
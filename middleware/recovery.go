@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"encoding/json"
 	"log"
 	"net/http"
 	"runtime/debug"
@@ -9,16 +8,18 @@ import (
 	"janus-backend-api/models"
 )
 
-// Recovery recovers from panics and returns a 500 error
+// Recovery recovers from panics and returns a 500 error. http.ErrAbortHandler is
+// re-raised so handlers can still abort a response that has already started.
 func Recovery(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
 				log.Printf("Panic recovered: %v\n%s", err, debug.Stack())
 
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(models.NewErrorResponse("Internal server error"))
+				WriteError(w, r, models.ErrInternal, "Internal server error")
 			}
 		}()
 
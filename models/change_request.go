@@ -0,0 +1,137 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Change request kinds
+const (
+	ChangeKindUpdate         = "update"
+	ChangeKindActivate       = "activate"
+	ChangeKindDeactivate     = "deactivate"
+	ChangeKindPolicy         = "policy"
+	ChangeKindAddApprover    = "add_approver"
+	ChangeKindRemoveApprover = "remove_approver"
+)
+
+// Change request states. Open requests become applied once enough approvals
+// arrive, or end as rejected, expired, cancelled or conflicted.
+const (
+	ChangeOpen       = "open"
+	ChangeApplied    = "applied"
+	ChangeRejected   = "rejected"
+	ChangeExpired    = "expired"
+	ChangeCancelled  = "cancelled"
+	ChangeConflicted = "conflicted"
+)
+
+// Change request audit actions. approval_dropped marks an approval that no
+// longer counts because its approver was removed.
+const (
+	ChangeEventCreated         = "created"
+	ChangeEventApproved        = "approved"
+	ChangeEventRejected        = "rejected"
+	ChangeEventCommented       = "commented"
+	ChangeEventApplied         = "applied"
+	ChangeEventExpired         = "expired"
+	ChangeEventCancelled       = "cancelled"
+	ChangeEventConflict        = "conflicted"
+	ChangeEventApprovalDropped = "approval_dropped"
+)
+
+// TriggerChangeRequest marks activations applied by an approved change request
+const TriggerChangeRequest = "change_request"
+
+// ConfigApprover is a user allowed to propose and approve changes to a config
+type ConfigApprover struct {
+	ConfigID  uuid.UUID `json:"config_id" gorm:"type:uuid;primaryKey;column:config_id"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey;column:user_id"`
+	AddedBy   uuid.UUID `json:"added_by" gorm:"type:uuid;column:added_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ConfigApprover) TableName() string {
+	return "config_approvers"
+}
+
+// ApprovalPolicyRequest turns the approval requirement of a config on or off
+type ApprovalPolicyRequest struct {
+	RequiresApproval  bool `json:"requires_approval"`
+	RequiredApprovals int  `json:"required_approvals,omitempty"`
+}
+
+// AddApproverRequest adds an approver by email
+type AddApproverRequest struct {
+	Email string `json:"email"`
+}
+
+// ChangeRequest proposes an update, activation, deactivation, approval policy or
+// approver change of a config that requires approval
+type ChangeRequest struct {
+	ChangeRequestID           uuid.UUID  `json:"change_request_id" gorm:"type:uuid;primaryKey;column:change_request_id"`
+	ConfigID                  uuid.UUID  `json:"config_id" gorm:"type:uuid;column:config_id"`
+	OwnerID                   uuid.UUID  `json:"owner_id" gorm:"type:uuid;column:owner_id"`
+	AuthorID                  uuid.UUID  `json:"author_id" gorm:"type:uuid;column:author_id"`
+	Kind                      string     `json:"kind" gorm:"column:kind"`
+	ProposedName              *string    `json:"proposed_name,omitempty" gorm:"column:proposed_name"`
+	ProposedConfig            JSONB      `json:"proposed_config,omitempty" gorm:"type:json;column:proposed_config"`
+	ProposedRequiresApproval  *bool      `json:"proposed_requires_approval,omitempty" gorm:"column:proposed_requires_approval"`
+	ProposedRequiredApprovals *int       `json:"proposed_required_approvals,omitempty" gorm:"column:proposed_required_approvals"`
+	ProposedApproverID        *uuid.UUID `json:"proposed_approver_id,omitempty" gorm:"type:uuid;column:proposed_approver_id"`
+	BaseVersion               int        `json:"base_version" gorm:"column:base_version"`
+	Status                    string     `json:"status" gorm:"column:status"`
+	RequiredApprovals         int        `json:"required_approvals" gorm:"column:required_approvals"`
+	Approvals                 int        `json:"approvals" gorm:"column:approvals"`
+	ExpiresAt                 time.Time  `json:"expires_at" gorm:"column:expires_at"`
+	ResolvedAt                *time.Time `json:"resolved_at,omitempty" gorm:"column:resolved_at"`
+	AppliedVersion            *int       `json:"applied_version,omitempty" gorm:"column:applied_version"`
+	CreatedAt                 time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ChangeRequest) TableName() string {
+	return "change_requests"
+}
+
+// ChangeRequestEvent is one entry of a change request's audit trail
+type ChangeRequestEvent struct {
+	EventID         uuid.UUID `json:"event_id" gorm:"type:uuid;primaryKey;column:event_id"`
+	ChangeRequestID uuid.UUID `json:"change_request_id" gorm:"type:uuid;column:change_request_id"`
+	ActorID         uuid.UUID `json:"actor_id" gorm:"type:uuid;column:actor_id"`
+	Action          string    `json:"action" gorm:"column:action"`
+	Comment         *string   `json:"comment,omitempty" gorm:"column:comment"`
+	CreatedAt       time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ChangeRequestEvent) TableName() string {
+	return "change_request_events"
+}
+
+// CreateChangeRequest proposes a change to a config
+type CreateChangeRequest struct {
+	Kind             string                 `json:"kind"`
+	ConfigName       string                 `json:"config_name,omitempty"`
+	Config           map[string]interface{} `json:"config,omitempty"`
+	AllowUnknownKeys bool                   `json:"allow_unknown_keys,omitempty"`
+	Policy           *ApprovalPolicyRequest `json:"policy,omitempty"`
+	ApproverEmail    string                 `json:"approver_email,omitempty"`
+	Comment          string                 `json:"comment,omitempty"`
+	ExpiresInHours   int                    `json:"expires_in_hours,omitempty"`
+}
+
+// ChangeRequestAction carries the comment of an approve, reject, comment or cancel action
+type ChangeRequestAction struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// ChangeRequestResponse is a change request with its audit trail and, while open,
+// the changes it would make to the current config
+type ChangeRequestResponse struct {
+	ChangeRequest
+	Changes []ConfigChange       `json:"changes,omitempty"`
+	Events  []ChangeRequestEvent `json:"events"`
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // ConfigStatus represents the status of a config
@@ -17,6 +18,28 @@ const (
 	ConfigStatusInactive ConfigStatus = "inactive"
 )
 
+// Sync states of a config pushed to Janus
+const (
+	SyncPending = "pending"
+	SyncSynced  = "synced"
+	SyncFailed  = "failed"
+)
+
+// JanusConfigClear is a pending request to tell Janus that a user no longer has
+// an active config. QueuedAt tells a newer request apart from one being sent.
+type JanusConfigClear struct {
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;primaryKey;column:user_id"`
+	QueuedAt   time.Time  `json:"queued_at" gorm:"column:queued_at"`
+	Attempts   int        `json:"attempts" gorm:"column:attempts"`
+	LastError  *string    `json:"last_error,omitempty" gorm:"column:last_error"`
+	NextSyncAt *time.Time `json:"next_sync_at,omitempty" gorm:"column:next_sync_at"`
+}
+
+// TableName specifies the table name for GORM
+func (JanusConfigClear) TableName() string {
+	return "janus_config_clears"
+}
+
 // JSONB type for JSON columns
 type JSONB map[string]interface{}
 
@@ -43,11 +66,29 @@ func (j *JSONB) Scan(value interface{}) error {
 
 // GlobalJobConfig represents a job configuration
 type GlobalJobConfig struct {
-	ConfigID   uuid.UUID    `json:"config_id" gorm:"type:uuid;primaryKey;column:config_id"`
-	UserID     uuid.UUID    `json:"user_id" gorm:"type:uuid;column:user_id"`
-	ConfigName *string      `json:"config_name" gorm:"column:config_name"`
-	Config     JSONB        `json:"config" gorm:"type:json;column:config"`
-	Status     ConfigStatus `json:"status" gorm:"type:config_status;column:status"`
+	ConfigID          uuid.UUID      `json:"config_id" gorm:"type:uuid;primaryKey;column:config_id"`
+	UserID            uuid.UUID      `json:"user_id" gorm:"type:uuid;column:user_id"`
+	ConfigName        *string        `json:"config_name" gorm:"column:config_name"`
+	Config            JSONB          `json:"config" gorm:"type:json;column:config"`
+	Status            ConfigStatus   `json:"status" gorm:"type:config_status;column:status"`
+	CurrentVersion    int            `json:"current_version" gorm:"column:current_version"`
+	CurrentRevisionID *uuid.UUID     `json:"current_revision_id" gorm:"type:uuid;column:current_revision_id"`
+	SyncStatus        *string        `json:"sync_status" gorm:"column:sync_status"`
+	SyncError         *string        `json:"sync_error" gorm:"column:sync_error"`
+	SyncAttempts      int            `json:"sync_attempts" gorm:"column:sync_attempts"`
+	NextSyncAt        *time.Time     `json:"next_sync_at" gorm:"column:next_sync_at"`
+	SyncedAt          *time.Time     `json:"synced_at" gorm:"column:synced_at"`
+	SyncedRevisionID  *uuid.UUID     `json:"synced_revision_id" gorm:"type:uuid;column:synced_revision_id"`
+	DriftDetectedAt   *time.Time     `json:"drift_detected_at" gorm:"column:drift_detected_at"`
+	RequiresApproval  bool           `json:"requires_approval" gorm:"column:requires_approval"`
+	RequiredApprovals int            `json:"required_approvals" gorm:"column:required_approvals"`
+	Labels            Labels         `json:"labels" gorm:"type:jsonb;column:labels"`
+	TemplateID        *uuid.UUID     `json:"template_id" gorm:"type:uuid;column:template_id"`
+	TemplateVersion   *int           `json:"template_version" gorm:"column:template_version"`
+	CreatedBy         *uuid.UUID     `json:"created_by" gorm:"type:uuid;column:created_by"`
+	CreatedAt         time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt         time.Time      `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:false"`
+	DeletedAt         gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
 }
 
 // TableName specifies the table name for GORM
@@ -57,14 +98,23 @@ func (GlobalJobConfig) TableName() string {
 
 // CreateConfigRequest for creating a new config
 type CreateConfigRequest struct {
-	ConfigName string                 `json:"config_name" binding:"required"`
-	Config     map[string]interface{} `json:"config" binding:"required"`
+	ConfigName       string                 `json:"config_name" binding:"required"`
+	Config           map[string]interface{} `json:"config" binding:"required"`
+	Labels           []string               `json:"labels,omitempty"`
+	AllowUnknownKeys bool                   `json:"allow_unknown_keys,omitempty"`
+}
+
+// CloneConfigRequest names the copy made by a clone; both fields are optional
+type CloneConfigRequest struct {
+	ConfigName string    `json:"config_name,omitempty"`
+	Labels     *[]string `json:"labels,omitempty"`
 }
 
 // UpdateConfigRequest for updating an existing config
 type UpdateConfigRequest struct {
-	ConfigName string                 `json:"config_name,omitempty"`
-	Config     map[string]interface{} `json:"config,omitempty"`
+	ConfigName       string                 `json:"config_name,omitempty"`
+	Config           map[string]interface{} `json:"config,omitempty"`
+	AllowUnknownKeys bool                   `json:"allow_unknown_keys,omitempty"`
 }
 
 // ConfigResponse returned to clients
@@ -74,6 +124,46 @@ type ConfigResponse struct {
 	Config     map[string]interface{} `json:"config"`
 	Status     string                 `json:"status"`
 	IsActive   bool                   `json:"is_active"`
+	Version    int                    `json:"version"`
+	RevisionID *uuid.UUID             `json:"revision_id,omitempty"`
+	Labels     []string               `json:"labels"`
+	CreatedBy  *uuid.UUID             `json:"created_by,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+	Template   *TemplateSource        `json:"template,omitempty"`
+	Sync       *ConfigSyncState       `json:"sync,omitempty"`
+	Approval   *ApprovalPolicy        `json:"approval,omitempty"`
+	Usage      *ConfigUsage           `json:"usage,omitempty"`
+	DeletedAt  *time.Time             `json:"deleted_at,omitempty"`
+}
+
+// TrashedConfigResponse is a deleted config and when it will be purged
+type TrashedConfigResponse struct {
+	ConfigResponse
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// TemplateSource identifies the gallery template a config was instantiated from
+type TemplateSource struct {
+	TemplateID uuid.UUID `json:"template_id"`
+	Version    int       `json:"version"`
+}
+
+// ApprovalPolicy is shown for configs whose changes need approval
+type ApprovalPolicy struct {
+	RequiresApproval  bool `json:"requires_approval"`
+	RequiredApprovals int  `json:"required_approvals"`
+}
+
+// ConfigSyncState reports whether Janus has the config
+type ConfigSyncState struct {
+	Status           string     `json:"status"`
+	Error            string     `json:"error,omitempty"`
+	Attempts         int        `json:"attempts"`
+	NextAttemptAt    *time.Time `json:"next_attempt_at,omitempty"`
+	SyncedAt         *time.Time `json:"synced_at,omitempty"`
+	SyncedRevisionID *uuid.UUID `json:"synced_revision_id,omitempty"`
+	DriftDetectedAt  *time.Time `json:"drift_detected_at,omitempty"`
 }
 
 // ToResponse converts GlobalJobConfig to ConfigResponse
@@ -82,13 +172,45 @@ func (c *GlobalJobConfig) ToResponse() ConfigResponse {
 	if c.ConfigName != nil {
 		name = *c.ConfigName
 	}
-	return ConfigResponse{
+	resp := ConfigResponse{
 		ConfigID:   c.ConfigID,
 		ConfigName: name,
 		Config:     c.Config,
 		Status:     string(c.Status),
 		IsActive:   c.Status == ConfigStatusActive,
+		Version:    c.CurrentVersion,
+		RevisionID: c.CurrentRevisionID,
+		Labels:     c.Labels,
+		CreatedBy:  c.CreatedBy,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
 	}
+	if resp.Labels == nil {
+		resp.Labels = []string{}
+	}
+	if c.DeletedAt.Valid {
+		resp.DeletedAt = &c.DeletedAt.Time
+	}
+	if c.TemplateID != nil && c.TemplateVersion != nil {
+		resp.Template = &TemplateSource{TemplateID: *c.TemplateID, Version: *c.TemplateVersion}
+	}
+	if c.RequiresApproval {
+		resp.Approval = &ApprovalPolicy{RequiresApproval: true, RequiredApprovals: c.RequiredApprovals}
+	}
+	if c.SyncStatus != nil {
+		resp.Sync = &ConfigSyncState{
+			Status:           *c.SyncStatus,
+			Attempts:         c.SyncAttempts,
+			NextAttemptAt:    c.NextSyncAt,
+			SyncedAt:         c.SyncedAt,
+			SyncedRevisionID: c.SyncedRevisionID,
+			DriftDetectedAt:  c.DriftDetectedAt,
+		}
+		if c.SyncError != nil {
+			resp.Sync.Error = *c.SyncError
+		}
+	}
+	return resp
 }
 
 // ServiceStatus represents the service status for a user
@@ -120,14 +242,16 @@ func (UserAssociation) TableName() string {
 
 // Job represents a job in the system
 type Job struct {
-	JobID          string     `json:"job_id" gorm:"type:text;primaryKey;column:job_id"`
-	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;column:user_id"`
-	JobPayload     JSONB      `json:"job_payload" gorm:"type:json;column:job_payload"`
-	BatchID        *string    `json:"batch_id" gorm:"column:batch_id"`
-	JobStatus      string     `json:"job_status" gorm:"type:job_status;column:job_status"`
-	Reason         *string    `json:"reason" gorm:"column:reason"`
-	CreatedAt      *time.Time `json:"created_at" gorm:"column:created_at"`
-	GlobalConfigID *uuid.UUID `json:"global_config_id" gorm:"type:uuid;column:global_config_id"`
+	JobID            string     `json:"job_id" gorm:"type:text;primaryKey;column:job_id"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;column:user_id"`
+	JobPayload       JSONB      `json:"job_payload" gorm:"type:json;column:job_payload"`
+	BatchID          *string    `json:"batch_id" gorm:"column:batch_id"`
+	JobStatus        string     `json:"job_status" gorm:"type:job_status;column:job_status"`
+	Reason           *string    `json:"reason" gorm:"column:reason"`
+	CreatedAt        *time.Time `json:"created_at" gorm:"column:created_at"`
+	GlobalConfigID   *uuid.UUID `json:"global_config_id" gorm:"type:uuid;column:global_config_id"`
+	ConfigRevisionID *uuid.UUID `json:"config_revision_id" gorm:"type:uuid;column:config_revision_id"`
+	ConfigVersion    *int       `json:"config_version" gorm:"column:config_version"`
 }
 
 // TableName specifies the table name for GORM
@@ -137,14 +261,18 @@ func (Job) TableName() string {
 
 // JobResponse returned to clients
 type JobResponse struct {
-	JobID          string                 `json:"job_id"`
-	UserID         uuid.UUID              `json:"user_id"`
-	JobPayload     map[string]interface{} `json:"job_payload"`
-	BatchID        string                 `json:"batch_id,omitempty"`
-	JobStatus      string                 `json:"job_status"`
-	Reason         string                 `json:"reason,omitempty"`
-	CreatedAt      *time.Time             `json:"created_at"`
-	GlobalConfigID string                 `json:"global_config_id,omitempty"`
+	JobID            string                 `json:"job_id"`
+	UserID           uuid.UUID              `json:"user_id"`
+	JobPayload       map[string]interface{} `json:"job_payload"`
+	BatchID          string                 `json:"batch_id,omitempty"`
+	JobStatus        string                 `json:"job_status"`
+	Reason           string                 `json:"reason,omitempty"`
+	CreatedAt        *time.Time             `json:"created_at"`
+	GlobalConfigID   string                 `json:"global_config_id,omitempty"`
+	ConfigName       string                 `json:"config_name,omitempty"`
+	ConfigDeleted    bool                   `json:"config_deleted,omitempty"`
+	ConfigRevisionID string                 `json:"config_revision_id,omitempty"`
+	ConfigVersion    *int                   `json:"config_version,omitempty"`
 }
 
 // ToResponse converts Job to JobResponse
@@ -161,15 +289,21 @@ func (j *Job) ToResponse() JobResponse {
 	if j.GlobalConfigID != nil {
 		configID = j.GlobalConfigID.String()
 	}
+	revisionID := ""
+	if j.ConfigRevisionID != nil {
+		revisionID = j.ConfigRevisionID.String()
+	}
 	return JobResponse{
-		JobID:          j.JobID,
-		UserID:         j.UserID,
-		JobPayload:     j.JobPayload,
-		BatchID:        batchID,
-		JobStatus:      j.JobStatus,
-		Reason:         reason,
-		CreatedAt:      j.CreatedAt,
-		GlobalConfigID: configID,
+		JobID:            j.JobID,
+		UserID:           j.UserID,
+		JobPayload:       j.JobPayload,
+		BatchID:          batchID,
+		JobStatus:        j.JobStatus,
+		Reason:           reason,
+		CreatedAt:        j.CreatedAt,
+		GlobalConfigID:   configID,
+		ConfigRevisionID: revisionID,
+		ConfigVersion:    j.ConfigVersion,
 	}
 }
 
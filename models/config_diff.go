@@ -0,0 +1,67 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Config change operations
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// ConfigChange is one difference between two config documents
+type ConfigChange struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// DiffConfigs returns the changes that turn old into new, ordered by path.
+// Nested objects are compared key by key; any other value is compared as a whole.
+func DiffConfigs(old, new map[string]interface{}) []ConfigChange {
+	changes := []ConfigChange{}
+	diffObjects("", old, new, &changes)
+	return changes
+}
+
+func diffObjects(prefix string, old, new map[string]interface{}, changes *[]ConfigChange) {
+	keys := make(map[string]struct{}, len(old)+len(new))
+	for k := range old {
+		keys[k] = struct{}{}
+	}
+	for k := range new {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		oldValue, inOld := old[k]
+		newValue, inNew := new[k]
+		switch {
+		case !inOld:
+			*changes = append(*changes, ConfigChange{Path: path, Op: ChangeAdded, New: newValue})
+		case !inNew:
+			*changes = append(*changes, ConfigChange{Path: path, Op: ChangeRemoved, Old: oldValue})
+		default:
+			oldMap, oldIsMap := oldValue.(map[string]interface{})
+			newMap, newIsMap := newValue.(map[string]interface{})
+			if oldIsMap && newIsMap {
+				diffObjects(path, oldMap, newMap, changes)
+			} else if !reflect.DeepEqual(oldValue, newValue) {
+				*changes = append(*changes, ConfigChange{Path: path, Op: ChangeChanged, Old: oldValue, New: newValue})
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "github.com/google/uuid"
+
+// Import actions
+const (
+	ImportCreate    = "create"
+	ImportUpdate    = "update"
+	ImportUnchanged = "unchanged"
+)
+
+// ConfigDocument is the portable form of a config used for import and export
+type ConfigDocument struct {
+	Name   string                 `json:"name" yaml:"name"`
+	Config map[string]interface{} `json:"config" yaml:"config"`
+}
+
+// ImportResult describes what happened (or would happen) to one imported document
+type ImportResult struct {
+	Name     string         `json:"name"`
+	Action   string         `json:"action"`
+	ConfigID *uuid.UUID     `json:"config_id,omitempty"`
+	Version  int            `json:"version,omitempty"`
+	Changes  []ConfigChange `json:"changes,omitempty"`
+}
+
+// ImportResponse summarizes an import
+type ImportResponse struct {
+	DryRun    bool           `json:"dry_run"`
+	Created   int            `json:"created"`
+	Updated   int            `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Results   []ImportResult `json:"results"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Label limits
+const (
+	MaxConfigLabels   = 32
+	MaxConfigLabelLen = 64
+)
+
+// Labels are free-form tags on a config, stored as a JSONB array so they can be
+// matched with @>
+type Labels []string
+
+// Value implements driver.Valuer for Labels
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner for Labels
+func (l *Labels) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan labels")
+	}
+	return json.Unmarshal(bytes, (*[]string)(l))
+}
+
+// SetLabelsRequest replaces the labels of a config
+type SetLabelsRequest struct {
+	Labels []string `json:"labels"`
+}
+
+// NormalizeLabels trims, de-duplicates and sorts labels. Problems are reported
+// against field, e.g. "labels[2]".
+func NormalizeLabels(field string, labels []string) (Labels, []FieldError) {
+	var errs []FieldError
+	if len(labels) > MaxConfigLabels {
+		errs = append(errs, FieldError{Field: field, Message: "must have at most " + strconv.Itoa(MaxConfigLabels) + " labels"})
+	}
+
+	seen := make(map[string]bool, len(labels))
+	normalized := Labels{}
+	for i, label := range labels {
+		label = strings.TrimSpace(label)
+		path := field + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case label == "":
+			errs = append(errs, FieldError{Field: path, Message: "must not be empty"})
+		case len(label) > MaxConfigLabelLen:
+			errs = append(errs, FieldError{Field: path, Message: "must be at most " + strconv.Itoa(MaxConfigLabelLen) + " characters"})
+		case !seen[label]:
+			seen[label] = true
+			normalized = append(normalized, label)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, errs
+}
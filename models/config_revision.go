@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Revision change types
+const (
+	RevisionCreated    = "created"
+	RevisionUpdated    = "updated"
+	RevisionRolledBack = "rolled_back"
+	RevisionCloned     = "cloned"
+)
+
+// ConfigRevision is an immutable snapshot of a config taken on every change
+type ConfigRevision struct {
+	RevisionID    uuid.UUID `json:"revision_id" gorm:"type:uuid;primaryKey;column:revision_id"`
+	ConfigID      uuid.UUID `json:"config_id" gorm:"type:uuid;column:config_id"`
+	Version       int       `json:"version" gorm:"column:version"`
+	ConfigName    *string   `json:"config_name" gorm:"column:config_name"`
+	Config        JSONB     `json:"config" gorm:"type:json;column:config"`
+	AuthorID      uuid.UUID `json:"author_id" gorm:"type:uuid;column:author_id"`
+	ChangeType    string    `json:"change_type" gorm:"column:change_type"`
+	SourceVersion *int      `json:"source_version,omitempty" gorm:"column:source_version"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ConfigRevision) TableName() string {
+	return "config_revisions"
+}
+
+// ConfigDiffResponse is the structured difference between two revisions
+type ConfigDiffResponse struct {
+	ConfigID    uuid.UUID      `json:"config_id"`
+	FromVersion int            `json:"from_version"`
+	ToVersion   int            `json:"to_version"`
+	FromName    string         `json:"from_name"`
+	ToName      string         `json:"to_name"`
+	Changes     []ConfigChange `json:"changes"`
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schedule kinds
+const (
+	ScheduleOnce   = "once"
+	ScheduleWindow = "window"
+)
+
+// Activation triggers recorded in the activation history
+const (
+	TriggerManual   = "manual"
+	TriggerSchedule = "schedule"
+	TriggerWindow   = "window"
+)
+
+// Activation actions
+const (
+	ActionActivated   = "activated"
+	ActionDeactivated = "deactivated"
+)
+
+// weekdays maps the accepted day names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ConfigSchedule activates a config at a point in time ("once") or during a
+// recurring weekly window ("window")
+type ConfigSchedule struct {
+	ScheduleID       uuid.UUID  `json:"schedule_id" gorm:"type:uuid;primaryKey;column:schedule_id"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;column:user_id"`
+	ConfigID         uuid.UUID  `json:"config_id" gorm:"type:uuid;column:config_id"`
+	Kind             string     `json:"kind" gorm:"column:kind"`
+	ActivateAt       *time.Time `json:"activate_at,omitempty" gorm:"column:activate_at"`
+	Days             string     `json:"-" gorm:"column:days"`
+	StartTime        string     `json:"start_time,omitempty" gorm:"column:start_time"`
+	EndTime          string     `json:"end_time,omitempty" gorm:"column:end_time"`
+	Timezone         string     `json:"timezone,omitempty" gorm:"column:timezone"`
+	FallbackConfigID *uuid.UUID `json:"fallback_config_id,omitempty" gorm:"type:uuid;column:fallback_config_id"`
+	Enabled          bool       `json:"enabled" gorm:"column:enabled"`
+	InWindow         bool       `json:"in_window" gorm:"column:in_window"`
+	FiredAt          *time.Time `json:"fired_at,omitempty" gorm:"column:fired_at"`
+	LastError        *string    `json:"last_error,omitempty" gorm:"column:last_error"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ConfigSchedule) TableName() string {
+	return "config_schedules"
+}
+
+// ScheduleResponse returned to clients
+type ScheduleResponse struct {
+	ConfigSchedule
+	DaysList []string `json:"days,omitempty"`
+}
+
+// ToResponse converts ConfigSchedule to ScheduleResponse
+func (s *ConfigSchedule) ToResponse() ScheduleResponse {
+	resp := ScheduleResponse{ConfigSchedule: *s}
+	if s.Days != "" {
+		resp.DaysList = strings.Split(s.Days, ",")
+	}
+	return resp
+}
+
+// WindowOpen reports whether now falls inside the schedule's weekly window.
+// A window whose end is before its start runs past midnight; its day is the day it opens.
+func (s *ConfigSchedule) WindowOpen(now time.Time) (bool, error) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return false, err
+	}
+	start, err := parseClock(s.StartTime)
+	if err != nil {
+		return false, err
+	}
+	end, err := parseClock(s.EndTime)
+	if err != nil {
+		return false, err
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+
+	if start < end {
+		return s.onDay(today) && minute >= start && minute < end, nil
+	}
+	// Overnight window: the late part belongs to today, the early part to yesterday
+	return (s.onDay(today) && minute >= start) || (s.onDay(yesterday) && minute < end), nil
+}
+
+func (s *ConfigSchedule) onDay(day time.Weekday) bool {
+	for _, name := range strings.Split(s.Days, ",") {
+		if d, ok := weekdays[name]; ok && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock converts "HH:MM" to minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// CreateScheduleRequest for creating a schedule
+type CreateScheduleRequest struct {
+	ConfigID         uuid.UUID  `json:"config_id"`
+	Kind             string     `json:"kind"`
+	ActivateAt       *time.Time `json:"activate_at,omitempty"`
+	Days             []string   `json:"days,omitempty"`
+	StartTime        string     `json:"start_time,omitempty"`
+	EndTime          string     `json:"end_time,omitempty"`
+	Timezone         string     `json:"timezone,omitempty"`
+	FallbackConfigID *uuid.UUID `json:"fallback_config_id,omitempty"`
+}
+
+// Validate checks the request and returns one error per invalid field
+func (req *CreateScheduleRequest) Validate() []FieldError {
+	var errs []FieldError
+	if req.ConfigID == uuid.Nil {
+		errs = append(errs, FieldError{Field: "config_id", Message: "is required"})
+	}
+
+	switch req.Kind {
+	case ScheduleOnce:
+		if req.ActivateAt == nil {
+			errs = append(errs, FieldError{Field: "activate_at", Message: "is required for once schedules"})
+		}
+	case ScheduleWindow:
+		if len(req.Days) == 0 {
+			errs = append(errs, FieldError{Field: "days", Message: "is required for window schedules"})
+		}
+		for i, day := range req.Days {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				errs = append(errs, FieldError{Field: fmt.Sprintf("days[%d]", i), Message: "must be one of sun, mon, tue, wed, thu, fri, sat"})
+			}
+		}
+		start, startErr := parseClock(req.StartTime)
+		if startErr != nil {
+			errs = append(errs, FieldError{Field: "start_time", Message: "must be HH:MM"})
+		}
+		end, endErr := parseClock(req.EndTime)
+		if endErr != nil {
+			errs = append(errs, FieldError{Field: "end_time", Message: "must be HH:MM"})
+		}
+		if startErr == nil && endErr == nil && start == end {
+			errs = append(errs, FieldError{Field: "end_time", Message: "must differ from start_time"})
+		}
+		if req.Timezone == "" {
+			errs = append(errs, FieldError{Field: "timezone", Message: "is required for window schedules"})
+		} else if _, err := time.LoadLocation(req.Timezone); err != nil {
+			errs = append(errs, FieldError{Field: "timezone", Message: "must be an IANA time zone such as Europe/Berlin"})
+		}
+		if req.FallbackConfigID != nil && *req.FallbackConfigID == req.ConfigID {
+			errs = append(errs, FieldError{Field: "fallback_config_id", Message: "must differ from config_id"})
+		}
+	default:
+		errs = append(errs, FieldError{Field: "kind", Message: "must be once or window"})
+	}
+	return errs
+}
+
+// ConfigActivation is one entry of a user's activation history
+type ConfigActivation struct {
+	ActivationID     uuid.UUID  `json:"activation_id" gorm:"type:uuid;primaryKey;column:activation_id"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;column:user_id"`
+	ConfigID         uuid.UUID  `json:"config_id" gorm:"type:uuid;column:config_id"`
+	Action           string     `json:"action" gorm:"column:action"`
+	PreviousConfigID *uuid.UUID `json:"previous_config_id,omitempty" gorm:"type:uuid;column:previous_config_id"`
+	Trigger          string     `json:"trigger" gorm:"column:trigger"`
+	ScheduleID       *uuid.UUID `json:"schedule_id,omitempty" gorm:"type:uuid;column:schedule_id"`
+	ActorID          *uuid.UUID `json:"actor_id,omitempty" gorm:"type:uuid;column:actor_id"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ConfigActivation) TableName() string {
+	return "config_activations"
+}
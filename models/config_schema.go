@@ -0,0 +1,177 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+)
+
+// JobConfigSpec is the typed form of GlobalJobConfig.Config
+type JobConfigSpec struct {
+	MinPriority            *int           `json:"min_priority,omitempty"`
+	MaxConcurrentPerTenant *int           `json:"max_concurrent_per_tenant,omitempty"`
+	DependencyLimits       map[string]int `json:"dependency_limits,omitempty"`
+}
+
+// ConfigFieldKind is the value type of a config field
+type ConfigFieldKind string
+
+const (
+	ConfigFieldInteger    ConfigFieldKind = "integer"
+	ConfigFieldIntegerMap ConfigFieldKind = "integer_map"
+)
+
+// ConfigField describes one key of a config document. New admission settings are
+// added here; validation and the published JSON Schema both follow this list.
+type ConfigField struct {
+	Name        string
+	Kind        ConfigFieldKind
+	Description string
+	Minimum     *int
+	Maximum     *int
+}
+
+// dependencyNamePattern restricts dependency_limits keys
+var dependencyNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// ConfigFields lists every known config key
+var ConfigFields = []ConfigField{
+	{
+		Name:        "min_priority",
+		Kind:        ConfigFieldInteger,
+		Description: "Jobs with a lower priority are rejected",
+		Minimum:     intPtr(0),
+	},
+	{
+		Name:        "max_concurrent_per_tenant",
+		Kind:        ConfigFieldInteger,
+		Description: "Maximum number of jobs admitted concurrently for a single tenant",
+		Minimum:     intPtr(1),
+	},
+	{
+		Name:        "dependency_limits",
+		Kind:        ConfigFieldIntegerMap,
+		Description: "Maximum concurrent usage of each external dependency, keyed by dependency name",
+		Minimum:     intPtr(0),
+	},
+}
+
+// ValidateConfig checks a config document against ConfigFields. Unknown keys are
+// rejected unless allowUnknown is set. Field paths are prefixed with "config.".
+func ValidateConfig(config map[string]interface{}, allowUnknown bool) []FieldError {
+	fields := make(map[string]ConfigField, len(ConfigFields))
+	for _, f := range ConfigFields {
+		fields[f.Name] = f
+	}
+
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []FieldError
+	for _, key := range keys {
+		path := "config." + key
+		field, ok := fields[key]
+		if !ok {
+			if !allowUnknown {
+				errs = append(errs, FieldError{Field: path, Message: "unknown key"})
+			}
+			continue
+		}
+
+		value := config[key]
+		switch field.Kind {
+		case ConfigFieldInteger:
+			errs = append(errs, validateInteger(path, value, field)...)
+		case ConfigFieldIntegerMap:
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				errs = append(errs, FieldError{Field: path, Message: "must be an object"})
+				continue
+			}
+			names := make([]string, 0, len(m))
+			for name := range m {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				entryPath := path + "." + name
+				if !dependencyNamePattern.MatchString(name) {
+					errs = append(errs, FieldError{Field: entryPath, Message: "key must be 1-64 letters, digits, '_', '.' or '-'"})
+					continue
+				}
+				errs = append(errs, validateInteger(entryPath, m[name], field)...)
+			}
+		}
+	}
+	return errs
+}
+
+func validateInteger(path string, value interface{}, field ConfigField) []FieldError {
+	n, ok := value.(float64)
+	if !ok || n != math.Trunc(n) {
+		return []FieldError{{Field: path, Message: "must be an integer"}}
+	}
+	if field.Minimum != nil && n < float64(*field.Minimum) {
+		return []FieldError{{Field: path, Message: fmt.Sprintf("must be at least %d", *field.Minimum)}}
+	}
+	if field.Maximum != nil && n > float64(*field.Maximum) {
+		return []FieldError{{Field: path, Message: fmt.Sprintf("must be at most %d", *field.Maximum)}}
+	}
+	return nil
+}
+
+// ParseConfigSpec converts a stored config into its typed form; unknown keys are ignored
+func ParseConfigSpec(config map[string]interface{}) JobConfigSpec {
+	var spec JobConfigSpec
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return spec
+	}
+	json.Unmarshal(raw, &spec)
+	return spec
+}
+
+// ConfigJSONSchema returns the config document schema as JSON Schema (draft 2020-12)
+func ConfigJSONSchema() map[string]interface{} {
+	properties := make(map[string]interface{}, len(ConfigFields))
+	for _, f := range ConfigFields {
+		number := map[string]interface{}{"type": "integer"}
+		if f.Minimum != nil {
+			number["minimum"] = *f.Minimum
+		}
+		if f.Maximum != nil {
+			number["maximum"] = *f.Maximum
+		}
+
+		switch f.Kind {
+		case ConfigFieldInteger:
+			number["description"] = f.Description
+			properties[f.Name] = number
+		case ConfigFieldIntegerMap:
+			properties[f.Name] = map[string]interface{}{
+				"type":                 "object",
+				"description":          f.Description,
+				"propertyNames":        map[string]interface{}{"pattern": dependencyNamePattern.String()},
+				"additionalProperties": number,
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  "/configs/schema",
+		"title":                "Janus admission config",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// How a config reached the user reading it
+const (
+	SharedWithUser = "user"
+	SharedWithOrg  = "org"
+)
+
+// ConfigShare grants read-only access to a config to one user or to every member
+// of an org
+type ConfigShare struct {
+	ShareID   uuid.UUID  `json:"share_id" gorm:"type:uuid;primaryKey;column:share_id"`
+	ConfigID  uuid.UUID  `json:"config_id" gorm:"type:uuid;column:config_id"`
+	UserID    *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid;column:user_id"`
+	OrgID     *uuid.UUID `json:"org_id,omitempty" gorm:"type:uuid;column:org_id"`
+	CreatedBy uuid.UUID  `json:"created_by" gorm:"type:uuid;column:created_by"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ConfigShare) TableName() string {
+	return "config_shares"
+}
+
+// CreateShareRequest shares a config with a user (by email) or an org the owner belongs to
+type CreateShareRequest struct {
+	Email string     `json:"email,omitempty"`
+	OrgID *uuid.UUID `json:"org_id,omitempty"`
+}
+
+// SharedConfigResponse is a config someone else shared with the caller
+type SharedConfigResponse struct {
+	ConfigResponse
+	OwnerID   uuid.UUID `json:"owner_id"`
+	SharedVia string    `json:"shared_via"`
+}
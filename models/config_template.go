@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionTemplatePulled marks revisions that took in a newer template version
+const RevisionTemplatePulled = "template_pulled"
+
+// ConfigTemplate is a config published to the gallery under a unique name.
+// Every publish from the source config adds a version.
+type ConfigTemplate struct {
+	TemplateID     uuid.UUID  `json:"template_id" gorm:"type:uuid;primaryKey;column:template_id"`
+	Name           string     `json:"name" gorm:"column:name"`
+	Description    string     `json:"description" gorm:"column:description"`
+	OwnerID        uuid.UUID  `json:"owner_id" gorm:"type:uuid;column:owner_id"`
+	SourceConfigID *uuid.UUID `json:"source_config_id,omitempty" gorm:"type:uuid;column:source_config_id"`
+	LatestVersion  int        `json:"latest_version" gorm:"column:latest_version"`
+	Labels         Labels     `json:"labels" gorm:"type:jsonb;column:labels"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ConfigTemplate) TableName() string {
+	return "config_templates"
+}
+
+// ConfigTemplateVersion is an immutable published snapshot of a template
+type ConfigTemplateVersion struct {
+	TemplateID    uuid.UUID `json:"template_id" gorm:"type:uuid;primaryKey;column:template_id"`
+	Version       int       `json:"version" gorm:"primaryKey;column:version"`
+	Config        JSONB     `json:"config" gorm:"type:json;column:config"`
+	SourceVersion int       `json:"source_version" gorm:"column:source_version"`
+	Changelog     string    `json:"changelog,omitempty" gorm:"column:changelog"`
+	PublishedAt   time.Time `json:"published_at" gorm:"column:published_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ConfigTemplateVersion) TableName() string {
+	return "config_template_versions"
+}
+
+// PublishTemplateRequest publishes a config, or a new version of its template
+type PublishTemplateRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Changelog   string `json:"changelog,omitempty"`
+}
+
+// InstantiateTemplateRequest creates a config from a template version
+type InstantiateTemplateRequest struct {
+	ConfigName string `json:"config_name,omitempty"`
+	Version    int    `json:"version,omitempty"`
+}
+
+// PullTemplateRequest moves a config to a template version; the latest by default
+type PullTemplateRequest struct {
+	Version int `json:"version,omitempty"`
+}
+
+// TemplateResponse is a gallery entry with the config of its latest version
+type TemplateResponse struct {
+	ConfigTemplate
+	Config map[string]interface{} `json:"config"`
+}
+
+// UpstreamResponse compares a config with the template it was instantiated from.
+// UpstreamChanges is what changed in the template since the config's version;
+// Changes is what pulling the latest version would do to the config as it is now.
+type UpstreamResponse struct {
+	ConfigID        uuid.UUID      `json:"config_id"`
+	TemplateID      uuid.UUID      `json:"template_id"`
+	TemplateName    string         `json:"template_name"`
+	CurrentVersion  int            `json:"current_version"`
+	LatestVersion   int            `json:"latest_version"`
+	UpdateAvailable bool           `json:"update_available"`
+	UpstreamChanges []ConfigChange `json:"upstream_changes"`
+	Changes         []ConfigChange `json:"changes"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "github.com/google/uuid"
+
+// ConfigUsage is how a config has been used, counted from its jobs. Accepted
+// and rejected jobs are Janus' admission decisions, not how the jobs ran.
+type ConfigUsage struct {
+	Batches      int64 `json:"batches"`
+	SingleJobs   int64 `json:"single_jobs"`
+	AcceptedJobs int64 `json:"accepted_jobs"`
+	RejectedJobs int64 `json:"rejected_jobs"`
+	TotalJobs    int64 `json:"total_jobs"`
+}
+
+// UsageCounters are the counters Janus keeps in user_association
+type UsageCounters struct {
+	NoOfBatches   int64 `json:"no_of_batches"`
+	NoOfJobs      int64 `json:"no_of_jobs"`
+	SucceededJobs int64 `json:"succeeded_jobs"`
+	FailedJobs    int64 `json:"failed_jobs"`
+	TotalJobs     int64 `json:"total_jobs"`
+}
+
+// ConfigUsageResponse is returned by GET /configs/{id}/usage. Usage is summed
+// over every user who ran jobs with the config.
+type ConfigUsageResponse struct {
+	ConfigID uuid.UUID `json:"config_id"`
+	Users    int64     `json:"users"`
+	ConfigUsage
+}
+
+// UsageDrift is one config and user whose counters disagree with the jobs table
+type UsageDrift struct {
+	ConfigID   uuid.UUID     `json:"config_id"`
+	ConfigName *string       `json:"config_name,omitempty"`
+	UserID     uuid.UUID     `json:"user_id"`
+	Stored     UsageCounters `json:"stored"`
+	Actual     ConfigUsage   `json:"actual"`
+}
+
+// UsageCheckResponse is returned by GET /configs/usage/check
+type UsageCheckResponse struct {
+	Consistent bool         `json:"consistent"`
+	Drift      []UsageDrift `json:"drift"`
+}
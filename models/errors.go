@@ -0,0 +1,163 @@
+package models
+
+import "net/http"
+
+// ErrorCode is a stable, machine-readable error identifier. Clients should branch on
+// codes rather than messages; messages may change, codes may not.
+type ErrorCode string
+
+// Error codes returned by the API
+const (
+	ErrUnauthenticated      ErrorCode = "UNAUTHENTICATED"
+	ErrInvalidToken         ErrorCode = "INVALID_TOKEN"
+	ErrInvalidRequestBody   ErrorCode = "INVALID_REQUEST_BODY"
+	ErrValidationFailed     ErrorCode = "VALIDATION_FAILED"
+	ErrInvalidID            ErrorCode = "INVALID_ID"
+	ErrInvalidQueryParam    ErrorCode = "INVALID_QUERY_PARAM"
+	ErrPayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	ErrForbidden            ErrorCode = "FORBIDDEN"
+	ErrPreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+	ErrPreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+	ErrInvalidCredentials   ErrorCode = "INVALID_CREDENTIALS"
+	ErrPasswordLoginBlocked ErrorCode = "PASSWORD_LOGIN_UNAVAILABLE"
+	ErrEmailTaken           ErrorCode = "EMAIL_ALREADY_REGISTERED"
+	ErrUserNotFound         ErrorCode = "USER_NOT_FOUND"
+	ErrConfigNotFound       ErrorCode = "CONFIG_NOT_FOUND"
+	ErrNoActiveConfig       ErrorCode = "NO_ACTIVE_CONFIG"
+	ErrConfigNotActive      ErrorCode = "CONFIG_NOT_ACTIVE"
+	ErrConfigActive         ErrorCode = "CONFIG_ACTIVE"
+	ErrApprovalRequired     ErrorCode = "APPROVAL_REQUIRED"
+	ErrChangeNotFound       ErrorCode = "CHANGE_REQUEST_NOT_FOUND"
+	ErrChangeClosed         ErrorCode = "CHANGE_REQUEST_CLOSED"
+	ErrSelfApproval         ErrorCode = "SELF_APPROVAL"
+	ErrAlreadyApproved      ErrorCode = "ALREADY_APPROVED"
+	ErrVersionNotFound      ErrorCode = "CONFIG_VERSION_NOT_FOUND"
+	ErrScheduleNotFound     ErrorCode = "SCHEDULE_NOT_FOUND"
+	ErrOverrideNotFound     ErrorCode = "OVERRIDE_NOT_FOUND"
+	ErrShareNotFound        ErrorCode = "SHARE_NOT_FOUND"
+	ErrOrgNotFound          ErrorCode = "ORG_NOT_FOUND"
+	ErrOrgNameTaken         ErrorCode = "ORG_NAME_TAKEN"
+	ErrTemplateNotFound     ErrorCode = "TEMPLATE_NOT_FOUND"
+	ErrTemplateNameTaken    ErrorCode = "TEMPLATE_NAME_TAKEN"
+	ErrNoUpstreamTemplate   ErrorCode = "NO_UPSTREAM_TEMPLATE"
+	ErrJobNotFound          ErrorCode = "JOB_NOT_FOUND"
+	ErrBatchNotFound        ErrorCode = "BATCH_NOT_FOUND"
+	ErrSubmissionNotFound   ErrorCode = "SUBMISSION_NOT_FOUND"
+	ErrExportNotFound       ErrorCode = "EXPORT_NOT_FOUND"
+	ErrExportNotReady       ErrorCode = "EXPORT_NOT_READY"
+	ErrRouteNotFound        ErrorCode = "ROUTE_NOT_FOUND"
+	ErrMethodNotAllowed     ErrorCode = "METHOD_NOT_ALLOWED"
+	ErrServicePaused        ErrorCode = "SERVICE_PAUSED"
+	ErrAlreadyPaused        ErrorCode = "SERVICE_ALREADY_PAUSED"
+	ErrNotPaused            ErrorCode = "SERVICE_NOT_PAUSED"
+	ErrJanusUnavailable     ErrorCode = "JANUS_UNAVAILABLE"
+	ErrNotImplemented       ErrorCode = "NOT_IMPLEMENTED"
+	ErrInternal             ErrorCode = "INTERNAL_ERROR"
+)
+
+// ErrorInfo describes an error code in the catalog
+type ErrorInfo struct {
+	Code   ErrorCode `json:"code"`
+	Status int       `json:"status"`
+	Title  string    `json:"title"`
+}
+
+// ErrorCatalog lists every error code with its HTTP status and a short, fixed title
+var ErrorCatalog = map[ErrorCode]ErrorInfo{
+	ErrUnauthenticated:      {ErrUnauthenticated, http.StatusUnauthorized, "Authentication required"},
+	ErrInvalidToken:         {ErrInvalidToken, http.StatusUnauthorized, "Invalid or expired token"},
+	ErrInvalidRequestBody:   {ErrInvalidRequestBody, http.StatusBadRequest, "Request body is not valid JSON"},
+	ErrValidationFailed:     {ErrValidationFailed, http.StatusBadRequest, "Request failed validation"},
+	ErrInvalidID:            {ErrInvalidID, http.StatusBadRequest, "Malformed resource ID"},
+	ErrInvalidQueryParam:    {ErrInvalidQueryParam, http.StatusBadRequest, "Invalid query parameter"},
+	ErrPayloadTooLarge:      {ErrPayloadTooLarge, http.StatusRequestEntityTooLarge, "Request body too large"},
+	ErrUnsupportedMediaType: {ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "Unsupported content type"},
+	ErrForbidden:            {ErrForbidden, http.StatusForbidden, "Not allowed"},
+	ErrPreconditionRequired: {ErrPreconditionRequired, http.StatusPreconditionRequired, "If-Match header required"},
+	ErrPreconditionFailed:   {ErrPreconditionFailed, http.StatusPreconditionFailed, "Resource has changed"},
+	ErrInvalidCredentials:   {ErrInvalidCredentials, http.StatusUnauthorized, "Invalid email or password"},
+	ErrPasswordLoginBlocked: {ErrPasswordLoginBlocked, http.StatusUnauthorized, "Account does not support password login"},
+	ErrEmailTaken:           {ErrEmailTaken, http.StatusConflict, "Email already registered"},
+	ErrUserNotFound:         {ErrUserNotFound, http.StatusNotFound, "User not found"},
+	ErrConfigNotFound:       {ErrConfigNotFound, http.StatusNotFound, "Config not found"},
+	ErrNoActiveConfig:       {ErrNoActiveConfig, http.StatusNotFound, "No active config"},
+	ErrConfigNotActive:      {ErrConfigNotActive, http.StatusConflict, "Config is not active"},
+	ErrConfigActive:         {ErrConfigActive, http.StatusConflict, "Config is active"},
+	ErrApprovalRequired:     {ErrApprovalRequired, http.StatusConflict, "Change requires approval"},
+	ErrChangeNotFound:       {ErrChangeNotFound, http.StatusNotFound, "Change request not found"},
+	ErrChangeClosed:         {ErrChangeClosed, http.StatusConflict, "Change request is closed"},
+	ErrSelfApproval:         {ErrSelfApproval, http.StatusForbidden, "Authors cannot approve their own change"},
+	ErrAlreadyApproved:      {ErrAlreadyApproved, http.StatusConflict, "Change already approved by this user"},
+	ErrVersionNotFound:      {ErrVersionNotFound, http.StatusNotFound, "Config version not found"},
+	ErrScheduleNotFound:     {ErrScheduleNotFound, http.StatusNotFound, "Schedule not found"},
+	ErrOverrideNotFound:     {ErrOverrideNotFound, http.StatusNotFound, "Tenant override not found"},
+	ErrShareNotFound:        {ErrShareNotFound, http.StatusNotFound, "Share not found"},
+	ErrOrgNotFound:          {ErrOrgNotFound, http.StatusNotFound, "Org not found"},
+	ErrOrgNameTaken:         {ErrOrgNameTaken, http.StatusConflict, "Org name already taken"},
+	ErrTemplateNotFound:     {ErrTemplateNotFound, http.StatusNotFound, "Template not found"},
+	ErrTemplateNameTaken:    {ErrTemplateNameTaken, http.StatusConflict, "Template name already taken"},
+	ErrNoUpstreamTemplate:   {ErrNoUpstreamTemplate, http.StatusConflict, "Config was not created from a template"},
+	ErrJobNotFound:          {ErrJobNotFound, http.StatusNotFound, "Job not found"},
+	ErrBatchNotFound:        {ErrBatchNotFound, http.StatusNotFound, "Batch not found"},
+	ErrSubmissionNotFound:   {ErrSubmissionNotFound, http.StatusNotFound, "Submission not found"},
+	ErrExportNotFound:       {ErrExportNotFound, http.StatusNotFound, "Export not found"},
+	ErrExportNotReady:       {ErrExportNotReady, http.StatusConflict, "Export is not ready"},
+	ErrRouteNotFound:        {ErrRouteNotFound, http.StatusNotFound, "Route not found"},
+	ErrMethodNotAllowed:     {ErrMethodNotAllowed, http.StatusMethodNotAllowed, "Method not allowed"},
+	ErrServicePaused:        {ErrServicePaused, http.StatusServiceUnavailable, "Submissions are paused"},
+	ErrAlreadyPaused:        {ErrAlreadyPaused, http.StatusConflict, "Submissions are already paused"},
+	ErrNotPaused:            {ErrNotPaused, http.StatusConflict, "Submissions are not paused"},
+	ErrJanusUnavailable:     {ErrJanusUnavailable, http.StatusBadGateway, "Janus service unavailable"},
+	ErrNotImplemented:       {ErrNotImplemented, http.StatusNotImplemented, "Not implemented"},
+	ErrInternal:             {ErrInternal, http.StatusInternalServerError, "Internal server error"},
+}
+
+// Status returns the HTTP status for the code, defaulting to 500 for unknown codes
+func (c ErrorCode) Status() int {
+	if info, ok := ErrorCatalog[c]; ok {
+		return info.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Title returns the fixed title for the code
+func (c ErrorCode) Title() string {
+	if info, ok := ErrorCatalog[c]; ok {
+		return info.Title
+	}
+	return http.StatusText(c.Status())
+}
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ProblemDetails is an RFC 7807 application/problem+json error body
+type ProblemDetails struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      ErrorCode    `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Current   interface{}  `json:"current,omitempty"`
+}
+
+// NewProblemDetails creates an RFC 7807 problem for the code
+func NewProblemDetails(code ErrorCode, message, instance, requestID string, details []FieldError) ProblemDetails {
+	return ProblemDetails{
+		Type:      "/errors/" + string(code),
+		Title:     code.Title(),
+		Status:    code.Status(),
+		Detail:    message,
+		Instance:  instance,
+		Code:      code,
+		RequestID: requestID,
+		Errors:    details,
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportStatus is the state of an asynchronous export
+type ExportStatus string
+
+// Export states
+const (
+	ExportPending   ExportStatus = "pending"
+	ExportRunning   ExportStatus = "running"
+	ExportCompleted ExportStatus = "completed"
+	ExportFailed    ExportStatus = "failed"
+)
+
+// Export formats
+const (
+	ExportCSV     = "csv"
+	ExportNDJSON  = "ndjson"
+	ExportParquet = "parquet"
+)
+
+// JobExport is an export of jobs that runs in the background and leaves a file to download
+type JobExport struct {
+	ExportID    uuid.UUID    `json:"export_id" gorm:"type:uuid;primaryKey;column:export_id"`
+	UserID      uuid.UUID    `json:"user_id" gorm:"type:uuid;column:user_id"`
+	BatchID     *string      `json:"batch_id" gorm:"column:batch_id"`
+	Format      string       `json:"format" gorm:"column:format"`
+	Query       string       `json:"query" gorm:"column:query"`
+	Status      ExportStatus `json:"status" gorm:"column:status"`
+	RowCount    int64        `json:"row_count" gorm:"column:row_count"`
+	SizeBytes   int64        `json:"size_bytes" gorm:"column:size_bytes"`
+	Error       *string      `json:"error" gorm:"column:error"`
+	FilePath    *string      `json:"-" gorm:"column:file_path"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"column:created_at"`
+	StartedAt   *time.Time   `json:"started_at" gorm:"column:started_at"`
+	HeartbeatAt *time.Time   `json:"-" gorm:"column:heartbeat_at"`
+	CompletedAt *time.Time   `json:"completed_at" gorm:"column:completed_at"`
+	ExpiresAt   *time.Time   `json:"expires_at" gorm:"column:expires_at"`
+}
+
+// TableName specifies the table name for GORM
+func (JobExport) TableName() string {
+	return "job_exports"
+}
+
+// JobExportResponse is an export with the link to its file once it is ready
+type JobExportResponse struct {
+	JobExport
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// ToResponse converts JobExport to JobExportResponse
+func (e *JobExport) ToResponse() JobExportResponse {
+	resp := JobExportResponse{JobExport: *e}
+	if e.Status == ExportCompleted {
+		resp.DownloadURL = "/exports/" + e.ExportID.String() + "/download"
+	}
+	return resp
+}
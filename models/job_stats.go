@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// Time-series bucket sizes
+const (
+	BucketMinute = "minute"
+	BucketHour   = "hour"
+	BucketDay    = "day"
+)
+
+// Time-series groupings
+const (
+	GroupByTenant     = "tenant"
+	GroupByConfig     = "config"
+	GroupByDependency = "dependency"
+)
+
+// TimeseriesPoint is the admission counts of one bucket
+type TimeseriesPoint struct {
+	Bucket   time.Time `json:"bucket"`
+	Accepted int64     `json:"accepted"`
+	Rejected int64     `json:"rejected"`
+}
+
+// TimeseriesSeries is one group's admission counts over time. Group is nil for
+// the ungrouped series and for jobs without a value for the grouping.
+type TimeseriesSeries struct {
+	Group    *string           `json:"group"`
+	Name     *string           `json:"name,omitempty"`
+	Accepted int64             `json:"accepted"`
+	Rejected int64             `json:"rejected"`
+	Points   []TimeseriesPoint `json:"points"`
+}
+
+// TimeseriesResponse is returned by GET /jobs/stats/timeseries. Every series has
+// a point for every bucket from From up to To, including empty ones.
+type TimeseriesResponse struct {
+	From          time.Time          `json:"from"`
+	To            time.Time          `json:"to"`
+	Bucket        string             `json:"bucket"`
+	GroupBy       string             `json:"group_by,omitempty"`
+	Series        []TimeseriesSeries `json:"series"`
+	GroupsOmitted int                `json:"groups_omitted,omitempty"`
+}
+
+// ReasonStatsResponse is returned by GET /jobs/stats/reasons. The previous period
+// has the same length as the requested one and ends where it starts.
+type ReasonStatsResponse struct {
+	From             time.Time    `json:"from"`
+	To               time.Time    `json:"to"`
+	PreviousFrom     time.Time    `json:"previous_from"`
+	TotalRejected    int64        `json:"total_rejected"`
+	PreviousRejected int64        `json:"previous_rejected"`
+	Reasons          []ReasonStat `json:"reasons"`
+	ReasonsOmitted   int64        `json:"reasons_omitted,omitempty"`
+}
+
+// Reason trends versus the previous period
+const (
+	TrendNew  = "new"
+	TrendUp   = "up"
+	TrendDown = "down"
+	TrendFlat = "flat"
+)
+
+// ReasonStat is one normalized rejection reason. Sample is one of the reasons as
+// Janus wrote it; ChangePct is nil when the reason did not occur before.
+type ReasonStat struct {
+	Reason         string            `json:"reason"`
+	Sample         string            `json:"sample"`
+	Count          int64             `json:"count"`
+	Share          float64           `json:"share"`
+	Previous       int64             `json:"previous"`
+	Change         int64             `json:"change"`
+	ChangePct      *float64          `json:"change_pct"`
+	Trend          string            `json:"trend"`
+	ExampleJobIDs  []string          `json:"example_job_ids"`
+	ByTenant       []ReasonBreakdown `json:"by_tenant"`
+	ByPriorityBand []ReasonBreakdown `json:"by_priority_band"`
+	ByConfig       []ReasonBreakdown `json:"by_config"`
+}
+
+// ReasonBreakdown is how often a reason occurred for one tenant, priority band or
+// config. Key is nil for jobs without a value.
+type ReasonBreakdown struct {
+	Key   *string `json:"key"`
+	Name  *string `json:"name,omitempty"`
+	Count int64   `json:"count"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// MergePatch applies an RFC 7386 JSON Merge Patch to target and returns the result.
+// Objects are merged recursively, null removes a key, and any other value replaces
+// the target outright. target is not modified.
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	result := make(map[string]interface{}, len(targetObject)+len(patchObject))
+	if ok {
+		for k, v := range targetObject {
+			result[k] = v
+		}
+	}
+
+	for k, v := range patchObject {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		result[k] = MergePatch(result[k], v)
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Org is a named group of users that configs can be shared with. Membership is
+// explicit: users only join an org when an existing member adds them.
+type Org struct {
+	OrgID     uuid.UUID `json:"org_id" gorm:"type:uuid;primaryKey;column:org_id"`
+	Name      string    `json:"name" gorm:"column:name"`
+	CreatedBy uuid.UUID `json:"created_by" gorm:"type:uuid;column:created_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Org) TableName() string {
+	return "orgs"
+}
+
+// OrgMember is a user belonging to an org
+type OrgMember struct {
+	OrgID     uuid.UUID `json:"org_id" gorm:"type:uuid;primaryKey;column:org_id"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey;column:user_id"`
+	AddedBy   uuid.UUID `json:"added_by" gorm:"type:uuid;column:added_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (OrgMember) TableName() string {
+	return "org_members"
+}
+
+// CreateOrgRequest creates an org with the caller as its first member
+type CreateOrgRequest struct {
+	Name string `json:"name"`
+}
+
+// AddOrgMemberRequest adds a member by email
+type AddOrgMemberRequest struct {
+	Email string `json:"email"`
+}
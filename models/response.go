@@ -2,10 +2,13 @@ package models
 
 // APIResponse represents a standard API response
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success   bool         `json:"success"`
+	Message   string       `json:"message,omitempty"`
+	Data      interface{}  `json:"data,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Code      ErrorCode    `json:"code,omitempty"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
 }
 
 // NewSuccessResponse creates a success response
@@ -18,10 +21,13 @@ func NewSuccessResponse(message string, data interface{}) APIResponse {
 }
 
 // NewErrorResponse creates an error response
-func NewErrorResponse(err string) APIResponse {
+func NewErrorResponse(code ErrorCode, err string, details []FieldError, requestID string) APIResponse {
 	return APIResponse{
-		Success: false,
-		Error:   err,
+		Success:   false,
+		Error:     err,
+		Code:      code,
+		Details:   details,
+		RequestID: requestID,
 	}
 }
 
@@ -60,3 +66,15 @@ type StatsResponse struct {
 	TotalConfigs  int64 `json:"total_configs"`
 	ActiveConfigs int64 `json:"active_configs"`
 }
+
+// CursorResponse is one page of a cursor-paginated listing. Cursors are opaque;
+// TotalItems is only present when a count was requested and may be an estimate.
+type CursorResponse struct {
+	Success         bool        `json:"success"`
+	Data            interface{} `json:"data"`
+	PerPage         int         `json:"per_page"`
+	NextCursor      *string     `json:"next_cursor"`
+	PrevCursor      *string     `json:"prev_cursor"`
+	TotalItems      *int64      `json:"total_items,omitempty"`
+	TotalIsEstimate bool        `json:"total_is_estimate,omitempty"`
+}
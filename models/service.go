@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Service states kept in service_status; users without a row are running
+const (
+	ServiceRunning = "running"
+	ServicePaused  = "paused"
+)
+
+// ServicePause records one pause of a user's submissions. A pause is open until
+// ResumedAt is set, by a resume or automatically once ResumeAt has passed.
+type ServicePause struct {
+	PauseID     uuid.UUID  `json:"pause_id" gorm:"type:uuid;primaryKey;column:pause_id"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;column:user_id"`
+	PausedBy    uuid.UUID  `json:"paused_by" gorm:"type:uuid;column:paused_by"`
+	Reason      string     `json:"reason" gorm:"column:reason"`
+	PausedAt    time.Time  `json:"paused_at" gorm:"column:paused_at"`
+	ResumeAt    *time.Time `json:"resume_at" gorm:"column:resume_at"`
+	ResumedAt   *time.Time `json:"resumed_at" gorm:"column:resumed_at"`
+	ResumedBy   *uuid.UUID `json:"resumed_by" gorm:"type:uuid;column:resumed_by"`
+	AutoResumed bool       `json:"auto_resumed" gorm:"column:auto_resumed"`
+}
+
+// TableName specifies the table name for GORM
+func (ServicePause) TableName() string {
+	return "service_pauses"
+}
+
+// PauseServiceRequest pauses submissions. ResumeAt or ResumeAfter (a Go
+// duration such as "30m") schedules an automatic resume.
+type PauseServiceRequest struct {
+	Reason      string     `json:"reason"`
+	ResumeAt    *time.Time `json:"resume_at,omitempty"`
+	ResumeAfter string     `json:"resume_after,omitempty"`
+}
+
+// ServiceStatusResponse is returned by the /service endpoints. Pause is the open
+// pause while paused, or the pause a resume just ended; it is nil when the
+// status was set outside the API.
+type ServiceStatusResponse struct {
+	Status string        `json:"status"`
+	Pause  *ServicePause `json:"pause,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShadowDiff records an admission decision that differed between primary and shadow Janus.
+// Statuses are lowercase job statuses, or missing and error for a decision one side
+// did not make; they are null when neither response had per-job decisions and only
+// the HTTP status codes differ. A shadow that could not be reached has HTTP status 0.
+type ShadowDiff struct {
+	DiffID            uuid.UUID `json:"diff_id" gorm:"type:uuid;primaryKey;column:diff_id"`
+	UserID            uuid.UUID `json:"user_id" gorm:"type:uuid;column:user_id"`
+	Endpoint          string    `json:"endpoint" gorm:"column:endpoint"`
+	JobID             *string   `json:"job_id" gorm:"column:job_id"`
+	PrimaryStatus     *string   `json:"primary_status" gorm:"column:primary_status"`
+	ShadowStatus      *string   `json:"shadow_status" gorm:"column:shadow_status"`
+	PrimaryReason     *string   `json:"primary_reason" gorm:"column:primary_reason"`
+	ShadowReason      *string   `json:"shadow_reason" gorm:"column:shadow_reason"`
+	PrimaryHTTPStatus int       `json:"primary_http_status" gorm:"column:primary_http_status"`
+	ShadowHTTPStatus  int       `json:"shadow_http_status" gorm:"column:shadow_http_status"`
+	CreatedAt         time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ShadowDiff) TableName() string {
+	return "shadow_diffs"
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SimulateConfigRequest selects the historical jobs to replay
+type SimulateConfigRequest struct {
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+	MaxFlipped int        `json:"max_flipped,omitempty"`
+}
+
+// OutcomeCounts counts accepted and rejected jobs
+type OutcomeCounts struct {
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+}
+
+// TenantSimulation is the per-tenant part of a simulation
+type TenantSimulation struct {
+	TenantID  string        `json:"tenant_id"`
+	Jobs      int           `json:"jobs"`
+	Projected OutcomeCounts `json:"projected"`
+	Actual    OutcomeCounts `json:"actual"`
+}
+
+// FlippedJob is a job whose projected outcome differs from what actually happened
+type FlippedJob struct {
+	JobID           string     `json:"job_id"`
+	BatchID         string     `json:"batch_id,omitempty"`
+	TenantID        string     `json:"tenant_id"`
+	Priority        int        `json:"priority"`
+	CreatedAt       *time.Time `json:"created_at"`
+	ActualStatus    string     `json:"actual_status"`
+	ActualReason    string     `json:"actual_reason,omitempty"`
+	ProjectedStatus string     `json:"projected_status"`
+	ProjectedReason string     `json:"projected_reason,omitempty"`
+}
+
+// SimulationResult is the projected impact of a config on historical jobs
+type SimulationResult struct {
+	ConfigID         uuid.UUID          `json:"config_id"`
+	Version          int                `json:"version"`
+	From             time.Time          `json:"from"`
+	To               time.Time          `json:"to"`
+	TotalJobs        int                `json:"total_jobs"`
+	Truncated        bool               `json:"truncated"`
+	Projected        OutcomeCounts      `json:"projected"`
+	Actual           OutcomeCounts      `json:"actual"`
+	ByReason         map[string]int     `json:"by_reason"`
+	ByDependency     map[string]int     `json:"by_dependency"`
+	ByTenant         []TenantSimulation `json:"by_tenant"`
+	NewlyRejected    int                `json:"newly_rejected"`
+	NewlyAccepted    int                `json:"newly_accepted"`
+	Flipped          []FlippedJob       `json:"flipped"`
+	FlippedTruncated bool               `json:"flipped_truncated"`
+}
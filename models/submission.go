@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Submission is a locally recorded attempt to submit work to Janus
+type Submission struct {
+	SubmissionID uuid.UUID `json:"submission_id" gorm:"type:uuid;primaryKey;column:submission_id"`
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;column:user_id"`
+	Endpoint     string    `json:"endpoint" gorm:"column:endpoint"`
+	RequestHash  *string   `json:"request_hash" gorm:"column:request_hash"`
+	SizeBytes    int64     `json:"size_bytes" gorm:"column:size_bytes"`
+	JanusStatus  *int      `json:"janus_status" gorm:"column:janus_status"`
+	LatencyMs    int64     `json:"latency_ms" gorm:"column:latency_ms"`
+	BatchID      *string   `json:"batch_id" gorm:"column:batch_id"`
+	Error        *string   `json:"error" gorm:"column:error"`
+	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Submission) TableName() string {
+	return "submissions"
+}
+
+// Fail records why the attempt did not complete
+func (s *Submission) Fail(err error) {
+	msg := err.Error()
+	s.Error = &msg
+}
@@ -0,0 +1,328 @@
+// Package parquet writes Apache Parquet files one row at a time with bounded
+// memory. It supports what exports need and nothing more: a flat schema of
+// optional string, int64 and timestamp columns, PLAIN encoding and no
+// compression. Rows are buffered into row groups, which are written out as
+// soon as they are full, so the output can be streamed to a client.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Type is the type of a column
+type Type int
+
+const (
+	// String is a UTF-8 string
+	String Type = iota
+	// Int64 is a signed 64-bit integer
+	Int64
+	// Timestamp is a point in time stored as milliseconds since the Unix epoch
+	Timestamp
+)
+
+// Parquet physical types, converted types and encodings used by the writer
+const (
+	physicalInt64     = 2
+	physicalByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	repetitionOptional = 1
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecUncompressed  = 0
+	pageTypeData       = 0
+)
+
+// Row groups are written when they reach either limit
+const (
+	DefaultRowGroupRows  = 10000
+	DefaultRowGroupBytes = 32 << 20
+)
+
+var magic = []byte("PAR1")
+
+// Column describes one column of the file
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Writer writes rows to a Parquet file. Close must be called to write the footer.
+type Writer struct {
+	out     io.Writer
+	offset  int64
+	columns []Column
+	buffers []columnBuffer
+	rows    int
+	groups  []rowGroup
+	total   int64
+	closed  bool
+}
+
+type columnBuffer struct {
+	present []bool
+	values  bytes.Buffer
+}
+
+type rowGroup struct {
+	rows   int
+	bytes  int64
+	chunks []columnChunk
+}
+
+type columnChunk struct {
+	offset int64
+	size   int64
+	values int
+}
+
+// NewWriter writes the file header to out and returns a Writer for columns
+func NewWriter(out io.Writer, columns []Column) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("parquet: no columns")
+	}
+	w := &Writer{out: out, columns: columns, buffers: make([]columnBuffer, len(columns))}
+	if err := w.write(magic); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write adds a row. Values are matched to columns by position: string for
+// String, int64 for Int64 and time.Time for Timestamp; nil is a null.
+func (w *Writer) Write(row []interface{}) error {
+	if w.closed {
+		return errors.New("parquet: write to closed writer")
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, want %d", len(row), len(w.columns))
+	}
+
+	for i, value := range row {
+		buf := &w.buffers[i]
+		if value == nil {
+			buf.present = append(buf.present, false)
+			continue
+		}
+		switch w.columns[i].Type {
+		case String:
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("parquet: column %s wants a string, got %T", w.columns[i].Name, value)
+			}
+			binary.Write(&buf.values, binary.LittleEndian, uint32(len(s)))
+			buf.values.WriteString(s)
+		case Int64:
+			n, ok := value.(int64)
+			if !ok {
+				return fmt.Errorf("parquet: column %s wants an int64, got %T", w.columns[i].Name, value)
+			}
+			binary.Write(&buf.values, binary.LittleEndian, n)
+		case Timestamp:
+			t, ok := value.(time.Time)
+			if !ok {
+				return fmt.Errorf("parquet: column %s wants a time.Time, got %T", w.columns[i].Name, value)
+			}
+			binary.Write(&buf.values, binary.LittleEndian, t.UnixMilli())
+		}
+		buf.present = append(buf.present, true)
+	}
+	w.rows++
+
+	if w.rows >= DefaultRowGroupRows || w.buffered() >= DefaultRowGroupBytes {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered rows as a row group
+func (w *Writer) Flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	group := rowGroup{rows: w.rows, chunks: make([]columnChunk, len(w.columns))}
+	for i := range w.columns {
+		start := w.offset
+		if err := w.writePage(&w.buffers[i]); err != nil {
+			return err
+		}
+		group.chunks[i] = columnChunk{offset: start, size: w.offset - start, values: w.rows}
+		group.bytes += w.offset - start
+		w.buffers[i] = columnBuffer{}
+	}
+	w.groups = append(w.groups, group)
+	w.total += int64(w.rows)
+	w.rows = 0
+	return nil
+}
+
+// Close flushes the last row group and writes the footer. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	footer := w.footer()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	if err := w.write(length[:]); err != nil {
+		return err
+	}
+	return w.write(magic)
+}
+
+func (w *Writer) buffered() int {
+	n := 0
+	for i := range w.buffers {
+		n += w.buffers[i].values.Len()
+	}
+	return n
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.out.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// writePage writes a column's buffered values as a single v1 data page:
+// definition levels, RLE encoded with a length prefix, followed by the
+// PLAIN encoded non-null values
+func (w *Writer) writePage(buf *columnBuffer) error {
+	levels := definitionLevels(buf.present)
+	size := 4 + len(levels) + buf.values.Len()
+
+	var header compactWriter
+	header.begin(0)
+	header.i32(1, pageTypeData)
+	header.i32(2, int32(size))
+	header.i32(3, int32(size))
+	header.begin(5)
+	header.i32(1, int32(len(buf.present)))
+	header.i32(2, encodingPlain)
+	header.i32(3, encodingRLE)
+	header.i32(4, encodingRLE)
+	header.end()
+	header.end()
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(levels)))
+	for _, b := range [][]byte{header.buf.Bytes(), length[:], levels, buf.values.Bytes()} {
+		if err := w.write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// definitionLevels encodes 0/1 definition levels as RLE runs of the
+// RLE/bit-packing hybrid encoding, bit width 1
+func definitionLevels(present []bool) []byte {
+	var out []byte
+	var varint [binary.MaxVarintLen64]byte
+	for i := 0; i < len(present); {
+		j := i
+		for j < len(present) && present[j] == present[i] {
+			j++
+		}
+		out = append(out, varint[:binary.PutUvarint(varint[:], uint64(j-i)<<1)]...)
+		if present[i] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i = j
+	}
+	return out
+}
+
+// footer encodes the FileMetaData
+func (w *Writer) footer() []byte {
+	var c compactWriter
+	c.begin(0)
+	c.i32(1, 1)
+
+	c.list(2, compactStruct, len(w.columns)+1)
+	c.begin(0)
+	c.binary(4, "schema")
+	c.i32(5, int32(len(w.columns)))
+	c.end()
+	for _, col := range w.columns {
+		c.begin(0)
+		c.i32(1, col.physical())
+		c.i32(3, repetitionOptional)
+		c.binary(4, col.Name)
+		if converted, ok := col.converted(); ok {
+			c.i32(6, converted)
+		}
+		c.end()
+	}
+
+	c.i64(3, w.total)
+
+	c.list(4, compactStruct, len(w.groups))
+	for _, group := range w.groups {
+		c.begin(0)
+		c.list(1, compactStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			c.begin(0)
+			c.i64(2, chunk.offset)
+			c.begin(3)
+			c.i32(1, w.columns[i].physical())
+			c.list(2, compactI32, 2)
+			c.zigzag(encodingPlain)
+			c.zigzag(encodingRLE)
+			c.list(3, compactBinary, 1)
+			c.rawBinary(w.columns[i].Name)
+			c.i32(4, codecUncompressed)
+			c.i64(5, int64(chunk.values))
+			c.i64(6, chunk.size)
+			c.i64(7, chunk.size)
+			c.i64(9, chunk.offset)
+			c.end()
+			c.end()
+		}
+		c.i64(2, group.bytes)
+		c.i64(3, int64(group.rows))
+		c.end()
+	}
+
+	c.binary(6, "janus-backend-api")
+	c.end()
+	return c.buf.Bytes()
+}
+
+func (c Column) physical() int32 {
+	if c.Type == String {
+		return physicalByteArray
+	}
+	return physicalInt64
+}
+
+func (c Column) converted() (int32, bool) {
+	switch c.Type {
+	case String:
+		return convertedUTF8, true
+	case Timestamp:
+		return convertedTimestampMillis, true
+	default:
+		return 0, false
+	}
+}
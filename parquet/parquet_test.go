@@ -0,0 +1,133 @@
+package parquet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/deprecated"
+)
+
+// TestRoundTrip writes a file and reads it back with parquet-go to check that
+// other readers accept the schema, row groups and values.
+func TestRoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "job_id", Type: String},
+		{Name: "config_version", Type: Int64},
+		{Name: "created_at", Type: Timestamp},
+	}
+	created := time.Date(2026, 3, 1, 12, 30, 45, 123e6, time.UTC)
+	rows := [][]interface{}{
+		{"a", int64(1), created},
+		{"", int64(-7), nil},
+		{nil, nil, created.Add(time.Hour)},
+		{"ü日本", int64(1) << 40, created.Add(-time.Hour)},
+	}
+
+	var out bytes.Buffer
+	w, err := NewWriter(&out, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+		// Split the rows over two row groups
+		if i == 1 {
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := pq.OpenFile(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if f.NumRows() != int64(len(rows)) {
+		t.Fatalf("NumRows = %d, want %d", f.NumRows(), len(rows))
+	}
+
+	fields := f.Schema().Fields()
+	if len(fields) != len(columns) {
+		t.Fatalf("schema has %d fields, want %d", len(fields), len(columns))
+	}
+	wantTypes := []pq.Type{pq.ByteArrayType, pq.Int64Type, pq.Int64Type}
+	for i, field := range fields {
+		if field.Name() != columns[i].Name {
+			t.Errorf("field %d is %q, want %q", i, field.Name(), columns[i].Name)
+		}
+		if !field.Optional() {
+			t.Errorf("field %s is not optional", field.Name())
+		}
+		if kind := field.Type().Kind(); kind != wantTypes[i].Kind() {
+			t.Errorf("field %s has kind %v, want %v", field.Name(), kind, wantTypes[i].Kind())
+		}
+	}
+	if ct := fields[0].Type().ConvertedType(); ct == nil || *ct != deprecated.UTF8 {
+		t.Errorf("job_id is not annotated as a UTF-8 string")
+	}
+	if ct := fields[1].Type().ConvertedType(); ct != nil {
+		t.Errorf("config_version is annotated as %v, want a plain int64", *ct)
+	}
+	if ct := fields[2].Type().ConvertedType(); ct == nil || *ct != deprecated.TimestampMillis {
+		t.Errorf("created_at is not annotated as a millisecond timestamp")
+	}
+
+	groups := f.RowGroups()
+	if len(groups) != 2 {
+		t.Fatalf("file has %d row groups, want 2", len(groups))
+	}
+	if groups[0].NumRows() != 2 || groups[1].NumRows() != 2 {
+		t.Fatalf("row groups have %d and %d rows, want 2 and 2", groups[0].NumRows(), groups[1].NumRows())
+	}
+
+	var got []pq.Row
+	for _, group := range groups {
+		rr := group.Rows()
+		buf := make([]pq.Row, group.NumRows())
+		n, err := rr.ReadRows(buf)
+		if n != len(buf) {
+			t.Fatalf("read %d rows, want %d: %v", n, len(buf), err)
+		}
+		rr.Close()
+		for _, row := range buf[:n] {
+			got = append(got, row.Clone())
+		}
+	}
+
+	for i, row := range rows {
+		for j, want := range row {
+			value := got[i][j]
+			if want == nil {
+				if !value.IsNull() {
+					t.Errorf("row %d column %s = %v, want null", i, columns[j].Name, value)
+				}
+				continue
+			}
+			if value.IsNull() {
+				t.Errorf("row %d column %s is null, want %v", i, columns[j].Name, want)
+				continue
+			}
+			switch want := want.(type) {
+			case string:
+				if value.String() != want {
+					t.Errorf("row %d column %s = %q, want %q", i, columns[j].Name, value.String(), want)
+				}
+			case int64:
+				if value.Int64() != want {
+					t.Errorf("row %d column %s = %d, want %d", i, columns[j].Name, value.Int64(), want)
+				}
+			case time.Time:
+				if value.Int64() != want.UnixMilli() {
+					t.Errorf("row %d column %s = %d, want %d", i, columns[j].Name, value.Int64(), want.UnixMilli())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type ids
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes Parquet metadata with the Thrift compact protocol.
+// Only what the writer needs is supported: i32, i64, binary, lists and structs.
+type compactWriter struct {
+	buf    bytes.Buffer
+	lastID int16
+	stack  []int16
+}
+
+func (c *compactWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	c.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (c *compactWriter) zigzag(v int64) {
+	c.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (c *compactWriter) field(id int16, typ byte) {
+	if delta := id - c.lastID; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		c.buf.WriteByte(typ)
+		c.zigzag(int64(id))
+	}
+	c.lastID = id
+}
+
+func (c *compactWriter) i32(id int16, v int32) {
+	c.field(id, compactI32)
+	c.zigzag(int64(v))
+}
+
+func (c *compactWriter) i64(id int16, v int64) {
+	c.field(id, compactI64)
+	c.zigzag(v)
+}
+
+func (c *compactWriter) binary(id int16, s string) {
+	c.field(id, compactBinary)
+	c.rawBinary(s)
+}
+
+func (c *compactWriter) rawBinary(s string) {
+	c.uvarint(uint64(len(s)))
+	c.buf.WriteString(s)
+}
+
+func (c *compactWriter) list(id int16, elem byte, size int) {
+	c.field(id, compactList)
+	if size < 15 {
+		c.buf.WriteByte(byte(size)<<4 | elem)
+	} else {
+		c.buf.WriteByte(0xf0 | elem)
+		c.uvarint(uint64(size))
+	}
+}
+
+// begin starts a struct, either as field id or, with id 0, as a list element
+func (c *compactWriter) begin(id int16) {
+	if id != 0 {
+		c.field(id, compactStruct)
+	}
+	c.stack = append(c.stack, c.lastID)
+	c.lastID = 0
+}
+
+func (c *compactWriter) end() {
+	c.buf.WriteByte(0)
+	c.lastID = c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"janus-backend-api/config"
 	"janus-backend-api/controllers"
 	"janus-backend-api/middleware"
 
@@ -9,21 +10,36 @@ import (
 )
 
 // SetupRouter configures all routes and returns the router
-func SetupRouter(janusBaseURL string) *chi.Mux {
+func SetupRouter(cfg *config.AppConfig) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Global middleware
+	r.Use(middleware.RequestID)
 	r.Use(chimiddleware.Logger)
-	r.Use(chimiddleware.Recoverer)
+	r.Use(middleware.Recovery)
 	r.Use(middleware.CORS)
 
+	// Structured errors for unknown routes and methods
+	r.NotFound(middleware.NotFound)
+	r.MethodNotAllowed(middleware.MethodNotAllowed)
+
 	// Initialize controllers
-	healthController := controllers.NewHealthController(janusBaseURL)
+	healthController := controllers.NewHealthController(cfg.JanusBaseURL)
+	errorController := controllers.NewErrorController()
 	authController := controllers.NewAuthController()
-	submitController := controllers.NewSubmitController(janusBaseURL)
-	configController := controllers.NewConfigController()
-	jobController := controllers.NewJobController()
+	submitController := controllers.NewSubmitController(cfg)
+	serviceController := controllers.NewServiceController()
+	configController := controllers.NewConfigController(cfg.ConfigTrashRetention)
+	jobController := controllers.NewJobController(cfg.JobQueryFields)
 	batchController := controllers.NewBatchController()
+	shadowController := controllers.NewShadowController()
+	submissionController := controllers.NewSubmissionController()
+	scheduleController := controllers.NewScheduleController()
+	changeRequestController := controllers.NewChangeRequestController(cfg.ChangeRequestTTL)
+	shareController := controllers.NewShareController()
+	orgController := controllers.NewOrgController()
+	templateController := controllers.NewTemplateController()
+	exportController := controllers.NewExportController(cfg)
 
 	// ====================
 	// Public Routes
@@ -33,6 +49,10 @@ func SetupRouter(janusBaseURL string) *chi.Mux {
 	r.Get("/health", healthController.Health)
 	r.Get("/status", healthController.Status)
 
+	// Error catalog
+	r.Get("/errors", errorController.List)
+	r.Get("/errors/{code}", errorController.Get)
+
 	// Auth (public)
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/register", authController.Register)
@@ -57,23 +77,112 @@ func SetupRouter(janusBaseURL string) *chi.Mux {
 			r.Post("/batch/atomic", submitController.SubmitBatchAtomic)
 		})
 
+		// Pausing and resuming submissions
+		r.Route("/service", func(r chi.Router) {
+			r.Get("/status", serviceController.Status)
+			r.Get("/pauses", serviceController.Pauses)
+			r.Post("/pause", serviceController.Pause)
+			r.Post("/resume", serviceController.Resume)
+		})
+
+		// Submission audit trail
+		r.Route("/submissions", func(r chi.Router) {
+			r.Get("/", submissionController.List)
+			r.Get("/{id}", submissionController.Get)
+		})
+
 		// Config Management
 		r.Route("/configs", func(r chi.Router) {
 			r.Get("/", configController.List)
 			r.Get("/active", configController.GetActive)
+			r.Get("/schema", configController.Schema)
+			r.Get("/trash", configController.Trash)
+			r.Get("/usage/check", configController.UsageCheck)
+			r.Get("/shared", shareController.Shared)
+			r.Get("/shared/{id}", shareController.GetShared)
+			r.Post("/import", configController.Import)
+			r.Get("/activations", scheduleController.Activations)
+			r.Route("/schedules", func(r chi.Router) {
+				r.Get("/", scheduleController.List)
+				r.Post("/", scheduleController.Create)
+				r.Get("/{scheduleId}", scheduleController.Get)
+				r.Delete("/{scheduleId}", scheduleController.Delete)
+			})
 			r.Post("/", configController.Create)
 			r.Get("/{id}", configController.Get)
 			r.Put("/{id}", configController.Update)
+			r.Patch("/{id}", configController.Patch)
 			r.Delete("/{id}", configController.Delete)
+			r.Post("/{id}/restore", configController.Restore)
+			r.Post("/{id}/clone", configController.Clone)
+			r.Put("/{id}/labels", configController.SetLabels)
+			r.Get("/{id}/shares", shareController.List)
+			r.Post("/{id}/shares", shareController.Create)
+			r.Delete("/{id}/shares/{shareId}", shareController.Delete)
+			r.Post("/{id}/publish", templateController.Publish)
+			r.Get("/{id}/upstream", templateController.Upstream)
+			r.Post("/{id}/upstream/pull", templateController.Pull)
 			r.Post("/{id}/activate", configController.Activate)
 			r.Post("/{id}/deactivate", configController.Deactivate)
+			r.Post("/{id}/sync", configController.Sync)
+			r.Get("/{id}/versions", configController.Versions)
+			r.Get("/{id}/versions/{version}", configController.GetVersion)
+			r.Get("/{id}/diff", configController.Diff)
+			r.Get("/{id}/export", configController.Export)
+			r.Post("/{id}/simulate", configController.Simulate)
+			r.Get("/{id}/effective", configController.Effective)
+			r.Get("/{id}/usage", configController.Usage)
+			r.Get("/{id}/overrides", configController.Overrides)
+			r.Get("/{id}/overrides/{tenantId}", configController.GetOverride)
+			r.Put("/{id}/overrides/{tenantId}", configController.SetOverride)
+			r.Delete("/{id}/overrides/{tenantId}", configController.DeleteOverride)
+			r.Put("/{id}/approval-policy", changeRequestController.SetPolicy)
+			r.Get("/{id}/approvers", changeRequestController.Approvers)
+			r.Post("/{id}/approvers", changeRequestController.AddApprover)
+			r.Delete("/{id}/approvers/{userId}", changeRequestController.RemoveApprover)
+			r.Post("/{id}/change-requests", changeRequestController.Create)
+			r.Post("/{id}/rollback/{version}", configController.Rollback)
+		})
+
+		// Change requests
+		r.Route("/change-requests", func(r chi.Router) {
+			r.Get("/", changeRequestController.List)
+			r.Get("/{id}", changeRequestController.Get)
+			r.Post("/{id}/approve", changeRequestController.Approve)
+			r.Post("/{id}/reject", changeRequestController.Reject)
+			r.Post("/{id}/comment", changeRequestController.Comment)
+			r.Post("/{id}/cancel", changeRequestController.Cancel)
+		})
+
+		// Orgs for sharing
+		r.Route("/orgs", func(r chi.Router) {
+			r.Get("/", orgController.List)
+			r.Post("/", orgController.Create)
+			r.Get("/{id}/members", orgController.Members)
+			r.Post("/{id}/members", orgController.AddMember)
+			r.Delete("/{id}/members/{userId}", orgController.RemoveMember)
+		})
+
+		// Template gallery
+		r.Route("/templates", func(r chi.Router) {
+			r.Get("/", templateController.List)
+			r.Get("/{id}", templateController.Get)
+			r.Delete("/{id}", templateController.Delete)
+			r.Get("/{id}/versions", templateController.Versions)
+			r.Get("/{id}/versions/{version}", templateController.GetVersion)
+			r.Post("/{id}/instantiate", templateController.Instantiate)
 		})
 
 		// Jobs
 		r.Route("/jobs", func(r chi.Router) {
 			r.Get("/", jobController.List)
 			r.Get("/stats", jobController.Stats)
+			r.Get("/stats/timeseries", jobController.Timeseries)
+			r.Get("/stats/reasons", jobController.Reasons)
+			r.Get("/export", exportController.Jobs)
+			r.Post("/export", exportController.CreateJobs)
 			r.Get("/{id}", jobController.Get)
+			r.Get("/{id}/shadow-diffs", shadowController.ForJob)
 		})
 
 		// Batches
@@ -81,7 +190,20 @@ func SetupRouter(janusBaseURL string) *chi.Mux {
 			r.Get("/", batchController.List)
 			r.Get("/{id}", batchController.Get)
 			r.Get("/{id}/jobs", batchController.GetJobs)
+			r.Get("/{id}/export", exportController.Batch)
+			r.Post("/{id}/export", exportController.CreateBatch)
 		})
+
+		// Asynchronous exports
+		r.Route("/exports", func(r chi.Router) {
+			r.Get("/", exportController.List)
+			r.Get("/{id}", exportController.Get)
+			r.Get("/{id}/download", exportController.Download)
+			r.Delete("/{id}", exportController.Delete)
+		})
+
+		// Shadow traffic
+		r.Get("/shadow/diffs", shadowController.List)
 	})
 
 	return r
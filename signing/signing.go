@@ -0,0 +1,117 @@
+// Package signing authenticates service-to-service requests between the API and Janus.
+//
+// The API signs each proxied request with an HMAC-SHA256 over the method, request URI,
+// timestamp, nonce, body digest and user ID. Janus (or a test double) verifies the
+// signature with Verifier, which also rejects stale and replayed requests.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers carried by signed requests
+const (
+	HeaderKeyID         = "X-Janus-Key-ID"
+	HeaderTimestamp     = "X-Janus-Timestamp"
+	HeaderNonce         = "X-Janus-Nonce"
+	HeaderContentSHA256 = "X-Janus-Content-SHA256"
+	HeaderSignature     = "X-Janus-Signature"
+	HeaderUserID        = "X-User-ID"
+)
+
+// Signer signs outgoing requests with a single active key
+type Signer struct {
+	keyID  string
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner creates a Signer for the given key ID and shared secret
+func NewSigner(keyID string, secret []byte) *Signer {
+	return &Signer{
+		keyID:  keyID,
+		secret: secret,
+		now:    time.Now,
+	}
+}
+
+// KeyID returns the ID of the key used for signing
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Sign adds signature headers to req. The user ID is read from the X-User-ID header,
+// so it must be set before signing. bodyDigest is the hex SHA-256 of the request body.
+func (s *Signer) Sign(req *http.Request, bodyDigest string) error {
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req.Header.Set(HeaderKeyID, s.keyID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderContentSHA256, bodyDigest)
+
+	sts := StringToSign(req.Method, req.URL.RequestURI(), timestamp, nonce, bodyDigest, req.Header.Get(HeaderUserID))
+	req.Header.Set(HeaderSignature, computeSignature(s.secret, sts))
+	return nil
+}
+
+// BodyDigest returns the hex-encoded SHA-256 of body
+func BodyDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// StringToSign builds the canonical string covered by the signature
+func StringToSign(method, requestURI, timestamp, nonce, bodyDigest, userID string) string {
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		timestamp,
+		nonce,
+		bodyDigest,
+		userID,
+	}, "\n")
+}
+
+// ParseKeys parses a key list in the form "kid1:secret1,kid2:secret2"
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("signing: invalid key entry %q", entry)
+		}
+		keys[id] = []byte(secret)
+	}
+	return keys, nil
+}
+
+func computeSignature(secret []byte, stringToSign string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("signing: failed to generate nonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}